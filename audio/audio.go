@@ -2,6 +2,7 @@ package audio
 
 import (
 	"sync"
+	"time"
 )
 
 // Global audio package variables for simple access
@@ -10,23 +11,19 @@ var (
 	Process  *AudioHostProcess           // Audio process management
 	Mutex    sync.RWMutex                // Global mutex for thread safety
 	Reconfig *AudioEngineReconfiguration // Configuration manager
+
+	DeviceOrder   DeviceOrdering // Ordering applied to audio device lists by LoadDevices
+	DataDir       string         // Base directory for standalone tools and data files
+	ToolTimeout   time.Duration  // Maximum run time for the devices and inspector tools
+	AudioHostPath string         // Explicit audio-host binary, overriding the data directory copy
+	AudioHostDir  string         // Working directory for audio-host; empty inherits the server's
+	AudioHostEnv  []string       // Extra KEY=VALUE entries added to audio-host's environment
 )
 
-// Initialize sets up the audio package
-func Initialize() error {
-	// Create the configuration manager
+// Initialize sets up the audio package. Device and plugin data are loaded
+// separately so the server can decide how to handle enumeration failures.
+func Initialize() {
 	Reconfig = NewAudioEngineReconfiguration()
-
-	// Load initial data
-	if err := LoadDevices(); err != nil {
-		return err
-	}
-
-	if err := LoadPlugins(); err != nil {
-		return err
-	}
-
-	return nil
 }
 
 // Shutdown cleans up audio resources
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Start breaker defaults: three failed launches within a minute pause further
+// launches for thirty seconds
+const (
+	DefaultBreakerThreshold = 3
+	DefaultBreakerWindow    = 1 * time.Minute
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// BreakerOpenError is returned while the start breaker is refusing launches
+type BreakerOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *BreakerOpenError) Error() string {
+	return fmt.Sprintf("audio-host failed to start repeatedly, retry in %v", e.RetryAfter.Round(time.Second))
+}
+
+// StartBreaker is a circuit breaker over audio-host launches. After Threshold
+// consecutive failures within Window it refuses launches for Cooldown, so a
+// client hammering start against broken hardware doesn't spawn and kill a
+// process per request.
+type StartBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  []time.Time
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// NewStartBreaker creates a closed breaker
+func NewStartBreaker(threshold int, window, cooldown time.Duration) *StartBreaker {
+	return &StartBreaker{Threshold: threshold, Window: window, Cooldown: cooldown, now: time.Now}
+}
+
+// StartFailures guards launches of the global audio-host process
+var StartFailures = NewStartBreaker(DefaultBreakerThreshold, DefaultBreakerWindow, DefaultBreakerCooldown)
+
+// Allow returns a BreakerOpenError while the breaker is cooling down
+func (b *StartBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if remaining := b.openUntil.Sub(b.now()); remaining > 0 {
+		return &BreakerOpenError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed launch, opening the breaker once Threshold
+// failures fall within Window
+func (b *StartBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	recent := b.failures[:0]
+	for _, at := range b.failures {
+		if now.Sub(at) < b.Window {
+			recent = append(recent, at)
+		}
+	}
+	b.failures = append(recent, now)
+
+	if b.Threshold > 0 && len(b.failures) >= b.Threshold {
+		b.openUntil = now.Add(b.Cooldown)
+		b.failures = nil
+	}
+}
+
+// RecordSuccess closes the breaker and forgets earlier failures
+func (b *StartBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = nil
+	b.openUntil = time.Time{}
+}
@@ -0,0 +1,67 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a breaker clock the test can advance
+func fakeClock(b *StartBreaker) *time.Time {
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	return &now
+}
+
+// TestStartBreakerOpensAfterThreshold verifies consecutive failures open the breaker for the cooldown
+func TestStartBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewStartBreaker(3, time.Minute, 30*time.Second)
+	now := fakeClock(b)
+
+	for i := 0; i < 2; i++ {
+		b.RecordFailure()
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Expected breaker closed after %d failures, got: %v", i+1, err)
+		}
+	}
+	b.RecordFailure()
+
+	var open *BreakerOpenError
+	if err := b.Allow(); !errors.As(err, &open) || open.RetryAfter != 30*time.Second {
+		t.Fatalf("Expected breaker open for 30s, got: %v", err)
+	}
+
+	*now = now.Add(31 * time.Second)
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected breaker closed after cooldown, got: %v", err)
+	}
+}
+
+// TestStartBreakerIgnoresOldFailures verifies failures outside the window don't count
+func TestStartBreakerIgnoresOldFailures(t *testing.T) {
+	b := NewStartBreaker(3, time.Minute, 30*time.Second)
+	now := fakeClock(b)
+
+	b.RecordFailure()
+	b.RecordFailure()
+	*now = now.Add(2 * time.Minute)
+	b.RecordFailure()
+
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected stale failures to be forgotten, got: %v", err)
+	}
+}
+
+// TestStartBreakerResetsOnSuccess verifies a successful start clears the failure count
+func TestStartBreakerResetsOnSuccess(t *testing.T) {
+	b := NewStartBreaker(2, time.Minute, 30*time.Second)
+	fakeClock(b)
+
+	b.RecordFailure()
+	b.RecordSuccess()
+	b.RecordFailure()
+
+	if err := b.Allow(); err != nil {
+		t.Errorf("Expected success to reset the breaker, got: %v", err)
+	}
+}
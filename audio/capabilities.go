@@ -0,0 +1,94 @@
+package audio
+
+// DirectionCapabilities describes a device's capabilities in one direction (input or output)
+type DirectionCapabilities struct {
+	ChannelCount         int           `json:"channelCount"`
+	IsDefault            bool          `json:"isDefault"`
+	SupportedSampleRates []int         `json:"supportedSampleRates"`
+	SupportedBitDepths   []int         `json:"supportedBitDepths"`
+	StreamFormat         *StreamFormat `json:"streamFormat,omitempty"`
+}
+
+// DeviceCapabilities is a consolidated view of one physical device. SampleRates
+// and BitDepths hold only the values every direction of the device supports, so
+// any combination of them is a valid choice for the UI.
+type DeviceCapabilities struct {
+	UID         string                 `json:"uid"`
+	DeviceID    int                    `json:"deviceId"`
+	Name        string                 `json:"name"`
+	IsOnline    bool                   `json:"isOnline"`
+	IsDefault   bool                   `json:"isDefault"`
+	SampleRates []int                  `json:"sampleRates"`
+	BitDepths   []int                  `json:"bitDepths"`
+	Input       *DirectionCapabilities `json:"input,omitempty"`
+	Output      *DirectionCapabilities `json:"output,omitempty"`
+}
+
+// GetDeviceCapabilities builds the capability summary for the device with the given UID.
+// It returns false if no audio device has that UID.
+func GetDeviceCapabilities(devices DevicesData, uid string) (DeviceCapabilities, bool) {
+	capabilities := DeviceCapabilities{UID: uid}
+	found := false
+
+	describe := func(device AudioDevice, defaultID int) *DirectionCapabilities {
+		found = true
+		capabilities.DeviceID = device.DeviceID
+		capabilities.Name = device.Name
+		capabilities.IsOnline = capabilities.IsOnline || device.IsOnline
+
+		direction := &DirectionCapabilities{
+			ChannelCount:         device.ChannelCount,
+			IsDefault:            device.IsDefault || (defaultID != 0 && device.DeviceID == defaultID),
+			SupportedSampleRates: device.SupportedSampleRates,
+			SupportedBitDepths:   device.SupportedBitDepths,
+			StreamFormat:         device.StreamFormat,
+		}
+		capabilities.IsDefault = capabilities.IsDefault || direction.IsDefault
+		return direction
+	}
+
+	for _, device := range devices.AudioInput {
+		if device.UID == uid {
+			capabilities.Input = describe(device, devices.Defaults.DefaultInput)
+			break
+		}
+	}
+	for _, device := range devices.AudioOutput {
+		if device.UID == uid {
+			capabilities.Output = describe(device, devices.Defaults.DefaultOutput)
+			break
+		}
+	}
+
+	if !found {
+		return DeviceCapabilities{}, false
+	}
+
+	switch {
+	case capabilities.Input != nil && capabilities.Output != nil:
+		capabilities.SampleRates = intersectInts(capabilities.Input.SupportedSampleRates, capabilities.Output.SupportedSampleRates)
+		capabilities.BitDepths = intersectInts(capabilities.Input.SupportedBitDepths, capabilities.Output.SupportedBitDepths)
+	case capabilities.Input != nil:
+		capabilities.SampleRates = capabilities.Input.SupportedSampleRates
+		capabilities.BitDepths = capabilities.Input.SupportedBitDepths
+	default:
+		capabilities.SampleRates = capabilities.Output.SupportedSampleRates
+		capabilities.BitDepths = capabilities.Output.SupportedBitDepths
+	}
+
+	return capabilities, true
+}
+
+// intersectInts returns the values present in both slices, in the order of a
+func intersectInts(a, b []int) []int {
+	result := []int{}
+	for _, value := range a {
+		for _, other := range b {
+			if value == other {
+				result = append(result, value)
+				break
+			}
+		}
+	}
+	return result
+}
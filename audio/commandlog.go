@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CommandLogSize is how many command/response pairs each audio-host process
+// keeps for debugging. Zero (the default) disables the log.
+var CommandLogSize = 0
+
+// CommandLogEntry records one command sent to audio-host and its outcome
+type CommandLogEntry struct {
+	Command    string        `json:"command"`
+	Response   string        `json:"response,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	SentAt     time.Time     `json:"sentAt"`
+	Duration   time.Duration `json:"-"`
+	DurationMs float64       `json:"durationMs"`
+}
+
+// CommandLog is a fixed-size ring buffer of recent commands
+type CommandLog struct {
+	mu      sync.Mutex
+	entries []CommandLogEntry
+	next    int
+	full    bool
+}
+
+// NewCommandLog creates a log holding the last size entries
+func NewCommandLog(size int) *CommandLog {
+	return &CommandLog{entries: make([]CommandLogEntry, size)}
+}
+
+// Record adds an entry, overwriting the oldest once the log is full
+func (l *CommandLog) Record(entry CommandLogEntry) {
+	entry.DurationMs = float64(entry.Duration) / float64(time.Millisecond)
+	slog.Debug("audio-host command",
+		"command", entry.Command, "response", entry.Response, "err", entry.Error, "durationMs", entry.DurationMs)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Entries returns the logged commands, oldest first
+func (l *CommandLog) Entries() []CommandLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		return append([]CommandLogEntry(nil), l.entries[:l.next]...)
+	}
+	result := make([]CommandLogEntry, 0, len(l.entries))
+	result = append(result, l.entries[l.next:]...)
+	return append(result, l.entries[:l.next]...)
+}
+
+// CommandLog returns the process's recent commands, or nil when logging is disabled
+func (p *AudioHostProcess) CommandLog() []CommandLogEntry {
+	if p.commandLog == nil {
+		return nil
+	}
+	return p.commandLog.Entries()
+}
@@ -0,0 +1,36 @@
+package audio
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestCommandLogRingBuffer verifies the log keeps the newest entries, oldest first
+func TestCommandLogRingBuffer(t *testing.T) {
+	log := NewCommandLog(3)
+	for i := 1; i <= 5; i++ {
+		log.Record(CommandLogEntry{Command: fmt.Sprintf("cmd%d", i), Duration: time.Duration(i) * time.Millisecond})
+	}
+
+	entries := log.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, expected := range []string{"cmd3", "cmd4", "cmd5"} {
+		if entries[i].Command != expected {
+			t.Errorf("Entry %d: expected %s, got %s", i, expected, entries[i].Command)
+		}
+	}
+	if entries[2].DurationMs != 5 {
+		t.Errorf("Expected 5ms duration, got %v", entries[2].DurationMs)
+	}
+}
+
+// TestCommandLogDisabled verifies processes without a log report nil
+func TestCommandLogDisabled(t *testing.T) {
+	p := &AudioHostProcess{}
+	if entries := p.CommandLog(); entries != nil {
+		t.Errorf("Expected nil entries when disabled, got %v", entries)
+	}
+}
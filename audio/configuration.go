@@ -2,7 +2,7 @@ package audio
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 )
 
 // NewAudioEngineReconfiguration creates a new reconfiguration manager
@@ -13,25 +13,33 @@ func NewAudioEngineReconfiguration() *AudioEngineReconfiguration {
 	}
 }
 
+// state returns the current configuration and running flag together
+func (r *AudioEngineReconfiguration) state() (*AudioConfig, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.currentConfig, r.isRunning
+}
+
 // AnalyzeConfigChange determines what type of reconfiguration is needed
 func (r *AudioEngineReconfiguration) AnalyzeConfigChange(newConfig AudioConfig) ChangeRequirement {
-	if r.currentConfig == nil {
+	current, _ := r.state()
+	if current == nil {
 		// First time configuration - no reconfiguration needed, just start
 		return NoChangeRequired
 	}
 
 	// Check for changes that require process restart (complete audio-host restart)
-	if r.requiresProcessRestart(*r.currentConfig, newConfig) {
+	if r.requiresProcessRestart(*current, newConfig) {
 		return ProcessRestartRequired
 	}
 
 	// Check for changes that require chain rebuild (stop/reconfigure/start audio unit)
-	if r.requiresChainRebuild(*r.currentConfig, newConfig) {
+	if r.requiresChainRebuild(*current, newConfig) {
 		return ChainRebuildRequired
 	}
 
 	// Check if it's a dynamic change (can be done while running)
-	if r.isDynamicChange(*r.currentConfig, newConfig) {
+	if r.isDynamicChange(*current, newConfig) {
 		return DynamicChangeOnly
 	}
 
@@ -42,20 +50,20 @@ func (r *AudioEngineReconfiguration) AnalyzeConfigChange(newConfig AudioConfig)
 func (r *AudioEngineReconfiguration) requiresProcessRestart(current, new AudioConfig) bool {
 	// Core audio parameters that require full process restart
 	if current.SampleRate != new.SampleRate {
-		log.Printf("🔄 Sample rate change detected: %.0f Hz → %.0f Hz (requires process restart)",
-			current.SampleRate, new.SampleRate)
+		slog.Debug("sample rate change requires process restart",
+			"from", current.SampleRate, "to", new.SampleRate)
 		return true
 	}
 
 	if current.BufferSize != new.BufferSize {
-		log.Printf("🔄 Buffer size change detected: %d → %d samples (requires process restart)",
-			current.BufferSize, new.BufferSize)
+		slog.Debug("buffer size change requires process restart",
+			"from", current.BufferSize, "to", new.BufferSize)
 		return true
 	}
 
 	if current.AudioInputDeviceID != new.AudioInputDeviceID {
-		log.Printf("🔄 Input device change detected: %d → %d (requires process restart)",
-			current.AudioInputDeviceID, new.AudioInputDeviceID)
+		slog.Debug("input device change requires process restart",
+			"from", current.AudioInputDeviceID, "to", new.AudioInputDeviceID)
 		return true
 	}
 
@@ -66,15 +74,15 @@ func (r *AudioEngineReconfiguration) requiresProcessRestart(current, new AudioCo
 func (r *AudioEngineReconfiguration) requiresChainRebuild(current, new AudioConfig) bool {
 	// Input channel changes could potentially be done with chain rebuild
 	if current.AudioInputChannel != new.AudioInputChannel {
-		log.Printf("🔧 Input channel change detected: %d → %d (could use chain rebuild)",
-			current.AudioInputChannel, new.AudioInputChannel)
+		slog.Debug("input channel change could use chain rebuild",
+			"from", current.AudioInputChannel, "to", new.AudioInputChannel)
 		return false
 	}
 
 	// Plugin path changes could be done with chain rebuild
 	if current.PluginPath != new.PluginPath {
-		log.Printf("🔧 Plugin path change detected: %s → %s (could use chain rebuild)",
-			current.PluginPath, new.PluginPath)
+		slog.Debug("plugin path change could use chain rebuild",
+			"from", current.PluginPath, "to", new.PluginPath)
 		return false
 	}
 
@@ -85,29 +93,82 @@ func (r *AudioEngineReconfiguration) requiresChainRebuild(current, new AudioConf
 func (r *AudioEngineReconfiguration) isDynamicChange(current, new AudioConfig) bool {
 	// Test tone enable/disable can be changed dynamically
 	if current.EnableTestTone != new.EnableTestTone {
-		log.Printf("🎵 Test tone change detected: %t → %t (dynamic change)",
-			current.EnableTestTone, new.EnableTestTone)
+		slog.Debug("test tone change is dynamic",
+			"from", current.EnableTestTone, "to", new.EnableTestTone)
 		return true
 	}
 
 	// Plugin loading/unloading can be done dynamically
 	if current.PluginPath != new.PluginPath {
-		log.Printf("🔌 Plugin change detected: %s → %s (dynamic change possible)",
-			current.PluginPath, new.PluginPath)
+		slog.Debug("plugin change is dynamic",
+			"from", current.PluginPath, "to", new.PluginPath)
 		return true
 	}
 
 	return false
 }
 
+// configParameters pairs each AudioConfig JSON key with a mutation changing only that field
+var configParameters = []struct {
+	name   string
+	mutate func(*AudioConfig)
+}{
+	{"sampleRate", func(c *AudioConfig) { c.SampleRate++ }},
+	{"bufferSize", func(c *AudioConfig) { c.BufferSize++ }},
+	{"audioInputDeviceID", func(c *AudioConfig) { c.AudioInputDeviceID++ }},
+	{"audioInputChannel", func(c *AudioConfig) { c.AudioInputChannel++ }},
+	{"enableTestTone", func(c *AudioConfig) { c.EnableTestTone = !c.EnableTestTone }},
+	{"pluginPath", func(c *AudioConfig) { c.PluginPath += "-changed" }},
+}
+
+// ChangeCapabilities reports which parameters can change live. While stopped
+// nothing can be interrupted, so every parameter is dynamic. While running,
+// a parameter is dynamic only if the same analysis used by ApplyConfigChange
+// classifies a change to it as DynamicChangeOnly.
+func (r *AudioEngineReconfiguration) ChangeCapabilities() ChangeCapabilities {
+	currentConfig, running := r.state()
+	capabilities := ChangeCapabilities{
+		Running:         running,
+		Dynamic:         []string{},
+		RequiresRestart: []string{},
+	}
+
+	var current AudioConfig
+	if currentConfig != nil {
+		current = *currentConfig
+	}
+
+	for _, parameter := range configParameters {
+		probe := current
+		parameter.mutate(&probe)
+
+		dynamic := !running ||
+			(!r.requiresProcessRestart(current, probe) &&
+				!r.requiresChainRebuild(current, probe) &&
+				r.isDynamicChange(current, probe))
+
+		if dynamic {
+			capabilities.Dynamic = append(capabilities.Dynamic, parameter.name)
+		} else {
+			capabilities.RequiresRestart = append(capabilities.RequiresRestart, parameter.name)
+		}
+	}
+	return capabilities
+}
+
 // ApplyConfigChange orchestrates the reconfiguration process
 func (r *AudioEngineReconfiguration) ApplyConfigChange(change ConfigChange) (*ReconfigurationResult, error) {
-	log.Printf("🎯 Analyzing config change: %s", change.ChangeReason)
+	slog.Info("analyzing config change", "reason", change.ChangeReason)
 
 	requirement := r.AnalyzeConfigChange(change.NewConfig)
+	current, running := r.state()
+	if change.RequireRunning && !running {
+		// Nothing is running to change in place, so the change is a (re)start
+		requirement = ProcessRestartRequired
+	}
 	result := &ReconfigurationResult{
 		ChangeType:     requirement,
-		PreviousConfig: r.currentConfig,
+		PreviousConfig: current,
 		NewConfig:      &change.NewConfig,
 	}
 
@@ -131,7 +192,7 @@ func (r *AudioEngineReconfiguration) ApplyConfigChange(change ConfigChange) (*Re
 
 // handleNoChange processes cases where no reconfiguration is needed
 func (r *AudioEngineReconfiguration) handleNoChange(result *ReconfigurationResult, change ConfigChange) (*ReconfigurationResult, error) {
-	log.Printf("✅ No configuration change required")
+	slog.Info("no configuration change required")
 
 	result.Success = true
 	result.Message = "Configuration unchanged"
@@ -139,55 +200,40 @@ func (r *AudioEngineReconfiguration) handleNoChange(result *ReconfigurationResul
 	result.ProcessIDChanged = false
 
 	// Update current config if this is first time setup
+	r.mu.Lock()
 	if r.currentConfig == nil {
 		r.currentConfig = &change.NewConfig
 		result.Message = "Initial configuration set"
 	}
+	r.mu.Unlock()
 
 	return result, nil
 }
 
 // handleProcessRestart manages complete audio-host process restart
 func (r *AudioEngineReconfiguration) handleProcessRestart(result *ReconfigurationResult, change ConfigChange) (*ReconfigurationResult, error) {
-	log.Printf("🔄 Process restart required for configuration change")
-
-	var oldPID int
+	slog.Info("process restart required for configuration change")
 
 	// Stop current audio-host if running
-	if r.isRunning && Process != nil {
-		oldPID = Process.pid
-		log.Printf("⏹️ Stopping current audio-host (PID %d)", oldPID)
-
-		if err := Process.Stop(); err != nil {
-			result.Success = false
-			result.Message = fmt.Sprintf("Failed to stop current audio-host: %v", err)
-			return result, err
-		}
-
-		// Clear global state
-		Mutex.Lock()
-		Process = nil
-		Mutex.Unlock()
-		r.isRunning = false
+	oldPID, err := r.Stop()
+	if err != nil {
+		result.Success = false
+		result.Message = fmt.Sprintf("Failed to stop current audio-host: %v", err)
+		return result, err
+	}
+	if oldPID != 0 {
+		slog.Info("stopped current audio-host", "pid", oldPID)
 	}
 
 	// Start new audio-host with new configuration
-	log.Printf("🚀 Starting audio-host with new configuration")
-	newProcess, err := StartAudioHostProcess(change.NewConfig)
+	slog.Info("starting audio-host with new configuration")
+	newProcess, err := r.Start(change.NewConfig)
 	if err != nil {
 		result.Success = false
 		result.Message = fmt.Sprintf("Failed to start audio-host with new configuration: %v", err)
 		return result, err
 	}
 
-	// Update global and local state
-	Mutex.Lock()
-	Process = newProcess
-	Mutex.Unlock()
-
-	r.currentConfig = &change.NewConfig
-	r.isRunning = true
-
 	result.Success = true
 	result.Message = "Audio-host restarted successfully with new configuration"
 	result.RequiredRestart = true
@@ -195,13 +241,13 @@ func (r *AudioEngineReconfiguration) handleProcessRestart(result *Reconfiguratio
 	result.OldPID = oldPID
 	result.NewPID = newProcess.pid
 
-	log.Printf("✅ Process restart completed: PID %d → PID %d", oldPID, newProcess.pid)
+	slog.Info("process restart completed", "oldPid", oldPID, "newPid", newProcess.pid)
 	return result, nil
 }
 
 // handleChainRebuild manages audio chain reconfiguration without process restart
 func (r *AudioEngineReconfiguration) handleChainRebuild(result *ReconfigurationResult, change ConfigChange) (*ReconfigurationResult, error) {
-	log.Printf("🔧 Audio chain rebuild required (not yet implemented)")
+	slog.Warn("audio chain rebuild not yet implemented, falling back to process restart")
 
 	result.Success = false
 	result.Message = "Chain rebuild not yet implemented - falling back to process restart"
@@ -212,86 +258,139 @@ func (r *AudioEngineReconfiguration) handleChainRebuild(result *ReconfigurationR
 
 // handleDynamicChange manages changes that can be made while audio is running
 func (r *AudioEngineReconfiguration) handleDynamicChange(result *ReconfigurationResult, change ConfigChange) (*ReconfigurationResult, error) {
-	log.Printf("🎵 Applying dynamic configuration change")
+	slog.Info("applying dynamic configuration change")
 
-	if !r.isRunning || Process == nil {
+	current, running := r.state()
+	Mutex.RLock()
+	process := Process
+	Mutex.RUnlock()
+	if !running || current == nil || process == nil {
 		result.Success = false
 		result.Message = "Cannot apply dynamic changes - audio-host not running"
 		return result, fmt.Errorf("audio-host not running")
 	}
 
 	// Handle test tone changes
-	if r.currentConfig.EnableTestTone != change.NewConfig.EnableTestTone {
+	if current.EnableTestTone != change.NewConfig.EnableTestTone {
 		command := "tone off"
 		if change.NewConfig.EnableTestTone {
 			command = "tone on"
 		}
 
-		_, err := Process.SendCommand(command)
+		_, err := process.SendCommand(command)
 		if err != nil {
 			result.Success = false
 			result.Message = fmt.Sprintf("Failed to change test tone: %v", err)
 			return result, err
 		}
-		log.Printf("🎵 Test tone changed: %t → %t", r.currentConfig.EnableTestTone, change.NewConfig.EnableTestTone)
+		slog.Info("test tone changed", "from", current.EnableTestTone, "to", change.NewConfig.EnableTestTone)
 	}
 
 	// Handle plugin changes
-	if r.currentConfig.PluginPath != change.NewConfig.PluginPath {
+	if current.PluginPath != change.NewConfig.PluginPath {
 		// Unload current plugin if any
-		if r.currentConfig.PluginPath != "" {
-			_, err := Process.SendCommand("unload-plugin")
+		if current.PluginPath != "" {
+			_, err := process.SendCommand("unload-plugin")
 			if err != nil {
-				log.Printf("⚠️ Warning: Failed to unload current plugin: %v", err)
+				slog.Warn("failed to unload current plugin", "err", err)
 			}
 		}
 
 		// Load new plugin if specified
 		if change.NewConfig.PluginPath != "" {
 			command := fmt.Sprintf("load-plugin %s", change.NewConfig.PluginPath)
-			_, err := Process.SendCommand(command)
+			_, err := process.SendCommand(command)
 			if err != nil {
 				result.Success = false
 				result.Message = fmt.Sprintf("Failed to load plugin: %v", err)
 				return result, err
 			}
-			log.Printf("🔌 Plugin changed: %s → %s", r.currentConfig.PluginPath, change.NewConfig.PluginPath)
+			slog.Info("plugin changed", "from", current.PluginPath, "to", change.NewConfig.PluginPath)
 		}
 	}
 
 	// Update current configuration
-	r.currentConfig = &change.NewConfig
+	r.SetCurrentConfig(change.NewConfig)
 
 	result.Success = true
 	result.Message = "Dynamic configuration change applied successfully"
 	result.RequiredRestart = false
 	result.ProcessIDChanged = false
 
-	log.Printf("✅ Dynamic change completed successfully")
+	slog.Info("dynamic change completed")
 	return result, nil
 }
 
+// Start launches audio-host with config, publishes it as the global process
+// and records what it actually runs at. Start, Stop and ApplyConfigChange are
+// the only paths that replace the global process, which keeps currentConfig
+// and isRunning in step with it.
+func (r *AudioEngineReconfiguration) Start(config AudioConfig) (*AudioHostProcess, error) {
+	process, err := StartAudioHostProcess(config)
+	if err != nil {
+		return nil, err
+	}
+
+	Mutex.Lock()
+	Process = process
+	Mutex.Unlock()
+
+	actual := process.ActualConfig()
+	r.mu.Lock()
+	r.currentConfig = &actual
+	r.isRunning = true
+	r.mu.Unlock()
+	return process, nil
+}
+
+// Stop stops the global audio-host process, if any, and marks the engine
+// stopped. It returns the PID of the stopped process, or 0 if there was none.
+// The last configuration is kept so a later change can be compared against it.
+func (r *AudioEngineReconfiguration) Stop() (int, error) {
+	Mutex.Lock()
+	process := Process
+	Process = nil
+	Mutex.Unlock()
+
+	r.SetRunning(false)
+	if process == nil {
+		return 0, nil
+	}
+
+	pid := process.GetPID()
+	if err := process.Stop(); err != nil {
+		return pid, err
+	}
+	return pid, nil
+}
+
 // GetCurrentConfig returns the current audio configuration
 func (r *AudioEngineReconfiguration) GetCurrentConfig() *AudioConfig {
-	return r.currentConfig
+	current, _ := r.state()
+	return current
 }
 
 // IsRunning returns whether the audio engine is currently running
 func (r *AudioEngineReconfiguration) IsRunning() bool {
-	return r.isRunning
+	_, running := r.state()
+	return running
 }
 
 // SetRunning updates the running state (should be called when audio starts/stops externally)
 func (r *AudioEngineReconfiguration) SetRunning(running bool) {
+	r.mu.Lock()
 	r.isRunning = running
+	r.mu.Unlock()
 	if !running {
-		log.Printf("🔇 Audio engine marked as stopped")
+		slog.Info("audio engine marked as stopped")
 	}
 }
 
 // SetCurrentConfig updates the current configuration (should be called when audio starts)
 func (r *AudioEngineReconfiguration) SetCurrentConfig(config AudioConfig) {
+	r.mu.Lock()
 	r.currentConfig = &config
-	log.Printf("🎯 Audio configuration updated: %.0f Hz, %d samples, device %d",
-		config.SampleRate, config.BufferSize, config.AudioInputDeviceID)
+	r.mu.Unlock()
+	slog.Info("audio configuration updated",
+		"sampleRate", config.SampleRate, "bufferSize", config.BufferSize, "inputDevice", config.AudioInputDeviceID)
 }
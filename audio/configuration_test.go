@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestChangeCapabilitiesStopped verifies every parameter is dynamic while audio is stopped
+func TestChangeCapabilitiesStopped(t *testing.T) {
+	r := NewAudioEngineReconfiguration()
+
+	capabilities := r.ChangeCapabilities()
+	if capabilities.Running {
+		t.Error("Expected stopped engine")
+	}
+	if len(capabilities.Dynamic) != len(configParameters) || len(capabilities.RequiresRestart) != 0 {
+		t.Errorf("Expected all parameters dynamic while stopped, got %+v", capabilities)
+	}
+}
+
+// TestChangeCapabilitiesRunning verifies core audio parameters require a restart while running
+func TestChangeCapabilitiesRunning(t *testing.T) {
+	r := NewAudioEngineReconfiguration()
+	r.currentConfig = &AudioConfig{SampleRate: 48000, BufferSize: 256, AudioInputDeviceID: 145}
+	r.isRunning = true
+
+	capabilities := r.ChangeCapabilities()
+
+	expectedDynamic := []string{"enableTestTone", "pluginPath"}
+	expectedRestart := []string{"sampleRate", "bufferSize", "audioInputDeviceID", "audioInputChannel"}
+	if !reflect.DeepEqual(capabilities.Dynamic, expectedDynamic) {
+		t.Errorf("Expected dynamic %v, got %v", expectedDynamic, capabilities.Dynamic)
+	}
+	if !reflect.DeepEqual(capabilities.RequiresRestart, expectedRestart) {
+		t.Errorf("Expected restart %v, got %v", expectedRestart, capabilities.RequiresRestart)
+	}
+}
+
+// TestReconfigurationConcurrentAccess verifies status reads can run alongside
+// state updates; run with -race to catch unguarded fields
+func TestReconfigurationConcurrentAccess(t *testing.T) {
+	r := NewAudioEngineReconfiguration()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.SetCurrentConfig(AudioConfig{SampleRate: 48000, BufferSize: 256})
+			r.SetRunning(i%2 == 0)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			r.ChangeCapabilities()
+			r.AnalyzeConfigChange(AudioConfig{SampleRate: 44100})
+			r.GetCurrentConfig()
+			r.IsRunning()
+		}
+	}()
+	wg.Wait()
+}
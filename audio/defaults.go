@@ -0,0 +1,54 @@
+package audio
+
+// Audio configuration defaults applied when a request leaves a field unset
+var (
+	// DefaultBufferSize balances latency against stability
+	DefaultBufferSize = 256
+	// DefaultSampleRate overrides the default output device's rate when non-zero
+	DefaultSampleRate float64
+)
+
+// DefaultAudioConfig returns the configuration used for unset fields: the
+// configured default buffer size, and DefaultSampleRate or else the rate the
+// default output device reports
+func DefaultAudioConfig() AudioConfig {
+	sampleRate := DefaultSampleRate
+	if sampleRate == 0 {
+		sampleRate = Devices().DefaultSampleRate
+	}
+	return AudioConfig{
+		SampleRate: sampleRate,
+		BufferSize: DefaultBufferSize,
+	}
+}
+
+// WithDefaults fills unset sample rate and buffer size from DefaultAudioConfig
+func (c AudioConfig) WithDefaults() AudioConfig {
+	defaults := DefaultAudioConfig()
+	if c.SampleRate == 0 {
+		c.SampleRate = defaults.SampleRate
+	}
+	if c.BufferSize == 0 {
+		c.BufferSize = defaults.BufferSize
+	}
+	return c
+}
+
+// Safe-mode fallback values: rates and buffer sizes every CoreAudio device handles
+const (
+	SafeModeSampleRate = 44100
+	SafeModeBufferSize = 256
+)
+
+// SafeModeConfig returns the conservative configuration a safe-mode start
+// falls back to: the system default input at 44100 Hz with a 256 sample buffer
+func SafeModeConfig(devices DevicesData) AudioConfig {
+	config := AudioConfig{SampleRate: SafeModeSampleRate, BufferSize: SafeModeBufferSize}
+	for _, device := range devices.AudioInput {
+		if device.DeviceID == devices.Defaults.DefaultInput {
+			config.AudioInputDeviceID = device.DeviceID
+			break
+		}
+	}
+	return config
+}
@@ -0,0 +1,49 @@
+package audio
+
+import (
+	"testing"
+)
+
+// TestWithDefaultsFillsUnsetFields verifies unset fields take the configured defaults
+func TestWithDefaultsFillsUnsetFields(t *testing.T) {
+	originalDevices, originalBuffer, originalRate := Data.Devices, DefaultBufferSize, DefaultSampleRate
+	defer func() {
+		Data.Devices, DefaultBufferSize, DefaultSampleRate = originalDevices, originalBuffer, originalRate
+	}()
+
+	Data.Devices.DefaultSampleRate = 44100
+	DefaultBufferSize = 512
+
+	config := AudioConfig{AudioInputDeviceID: 145}.WithDefaults()
+	if config.BufferSize != 512 || config.SampleRate != 44100 {
+		t.Errorf("Expected 512 samples at the device rate 44100, got %+v", config)
+	}
+
+	DefaultSampleRate = 96000
+	if config := (AudioConfig{}).WithDefaults(); config.SampleRate != 96000 {
+		t.Errorf("Expected configured sample rate to override the device rate, got %v", config.SampleRate)
+	}
+
+	explicit := AudioConfig{SampleRate: 48000, BufferSize: 64}
+	if got := explicit.WithDefaults(); got != explicit {
+		t.Errorf("Expected explicit values to be kept, got %+v", got)
+	}
+}
+
+// TestSafeModeConfig verifies the fallback uses the default input only when it exists
+func TestSafeModeConfig(t *testing.T) {
+	devices := DevicesData{
+		AudioInput: []AudioDevice{{DeviceID: 145, Name: "Steep II", ChannelCount: 2}},
+		Defaults:   DefaultDevices{DefaultInput: 145, DefaultOutput: 87},
+	}
+
+	config := SafeModeConfig(devices)
+	if config.SampleRate != 44100 || config.BufferSize != 256 || config.AudioInputDeviceID != 145 {
+		t.Errorf("Unexpected safe-mode config: %+v", config)
+	}
+
+	devices.Defaults.DefaultInput = 999
+	if config := SafeModeConfig(devices); config.AudioInputDeviceID != 0 {
+		t.Errorf("Expected no input for a missing default, got %d", config.AudioInputDeviceID)
+	}
+}
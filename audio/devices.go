@@ -1,52 +1,221 @@
 package audio
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+// DefaultToolTimeout bounds how long the devices and inspector tools may run
+const DefaultToolTimeout = 30 * time.Second
+
+// runTool runs a standalone tool and returns its stdout, killing it if it
+// runs longer than ToolTimeout so a hung tool can't block server startup.
+// Cancelling parent kills the tool early, and a deadline on parent replaces
+// ToolTimeout for that call.
+func runTool(parent context.Context, relativePath string) ([]byte, error) {
+	timeout := ToolTimeout
+	if timeout <= 0 {
+		timeout = DefaultToolTimeout
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	if _, ok := parent.Deadline(); !ok {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	}
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, ResolvePath(relativePath))
+	// Don't wait forever on pipes held open by grandchildren of a killed tool
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.Output()
+	if parentErr := parent.Err(); parentErr != nil {
+		return nil, fmt.Errorf("%s aborted: %w", filepath.Base(relativePath), parentErr)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%s timed out after %v", filepath.Base(relativePath), timeout)
+	}
+	return output, err
+}
+
+// deviceSequence numbers device enumerations so consumers can order results
+// and tell a fresh load from a cached one
+var deviceSequence atomic.Uint64
+
 // LoadDevices loads audio device information using the standalone devices tool
 func LoadDevices() error {
-	log.Println("Loading device information...")
+	return LoadDevicesContext(context.Background())
+}
 
-	cmd := exec.Command("./standalone/devices/devices")
-	output, err := cmd.Output()
+// LoadDevicesContext is LoadDevices with a context; cancelling ctx kills the
+// devices tool and leaves the loaded device data untouched
+func LoadDevicesContext(ctx context.Context) error {
+	slog.Info("loading device information")
+
+	output, err := runTool(ctx, DevicesToolPath)
 	if err != nil {
-		return fmt.Errorf("failed to run devices tool: %v", err)
+		return fmt.Errorf("failed to run devices tool: %w", err)
 	}
 
-	err = json.Unmarshal(output, &Data.Devices)
+	devices, err := parseDevices(output)
 	if err != nil {
-		return fmt.Errorf("failed to parse devices JSON: %v", err)
+		return err
+	}
+	for category, reason := range devices.Errors {
+		slog.Warn("device category failed to enumerate", "category", category, "err", reason)
 	}
 
-	log.Printf("✅ Loaded %d audio input devices, %d audio output devices, %d MIDI input devices, %d MIDI output devices",
-		Data.Devices.TotalAudioInputDevices,
-		Data.Devices.TotalAudioOutputDevices,
-		Data.Devices.TotalMIDIInputDevices,
-		Data.Devices.TotalMIDIOutputDevices)
+	Mutex.Lock()
+	changed := Data.Devices.Hash() != devices.Hash()
+	Data.Devices = devices
+	Mutex.Unlock()
+
+	slog.Info("loaded devices", "changed", changed,
+		"audioInputs", devices.TotalAudioInputDevices,
+		"audioOutputs", devices.TotalAudioOutputDevices,
+		"midiInputs", devices.TotalMIDIInputDevices,
+		"midiOutputs", devices.TotalMIDIOutputDevices)
 
 	return nil
 }
 
+// parseDevices decodes devices tool output, dropping devices that have no
+// channels in the direction of the list they appear in
+func parseDevices(output []byte) (DevicesData, error) {
+	// Decode into a fresh value so a bad refresh never leaves Data half-updated
+	var devices DevicesData
+	if err := json.Unmarshal(output, &devices); err != nil {
+		return DevicesData{}, fmt.Errorf("failed to parse devices JSON: %v", err)
+	}
+	devices.LoadedAt = time.Now()
+	devices.Sequence = deviceSequence.Add(1)
+
+	devices.AudioInput = WithChannels(devices.AudioInput)
+	devices.AudioOutput = WithChannels(devices.AudioOutput)
+	expandSampleRates(devices.AudioInput)
+	expandSampleRates(devices.AudioOutput)
+	reconcileDefault(devices.AudioInput, devices.Defaults.DefaultInput)
+	reconcileDefault(devices.AudioOutput, devices.Defaults.DefaultOutput)
+	devices.TotalAudioInputDevices = len(devices.AudioInput)
+	devices.TotalAudioOutputDevices = len(devices.AudioOutput)
+
+	if DeviceOrder == OrderDefaultFirst {
+		SortAudioDevices(devices.AudioInput, devices.Defaults.DefaultInput)
+		SortAudioDevices(devices.AudioOutput, devices.Defaults.DefaultOutput)
+	}
+	return devices, nil
+}
+
+// WithChannels returns the devices that have at least one channel. The devices
+// tool lists each device per direction with that direction's channel count,
+// so a pure output device can show up in the input list with zero channels.
+func WithChannels(devices []AudioDevice) []AudioDevice {
+	result := make([]AudioDevice, 0, len(devices))
+	for _, device := range devices {
+		if device.ChannelCount > 0 {
+			result = append(result, device)
+		} else {
+			slog.Debug("skipping device without channels", "deviceId", device.DeviceID, "name", device.Name)
+		}
+	}
+	return result
+}
+
+// expandSampleRates fills in supported rates from the reported ranges, for
+// devices tool builds that list ranges but leave out rates outside the
+// common set
+func expandSampleRates(devices []AudioDevice) {
+	for i := range devices {
+		if len(devices[i].SampleRateRanges) == 0 {
+			continue
+		}
+		rates := append(slices.Clone(devices[i].SupportedSampleRates), ExpandSampleRateRanges(devices[i].SampleRateRanges)...)
+		slices.Sort(rates)
+		devices[i].SupportedSampleRates = slices.Compact(rates)
+	}
+}
+
+// reconcileDefault makes IsDefault agree with the system default reported in
+// Defaults, so at most one device claims to be the default. The devices tool
+// reports isDefault false for every device and carries the real default only
+// in Defaults. Without a reported default, only the first flagged device keeps
+// its flag.
+func reconcileDefault(devices []AudioDevice, defaultID int) {
+	seen := false
+	for i := range devices {
+		if defaultID != 0 {
+			devices[i].IsDefault = devices[i].DeviceID == defaultID
+			continue
+		}
+		if devices[i].IsDefault && seen {
+			devices[i].IsDefault = false
+		}
+		seen = seen || devices[i].IsDefault
+	}
+}
+
+// SortAudioDevices orders devices for UI selection: the default device first,
+// then online devices alphabetically by name, with offline devices last.
+// A device counts as default if it is flagged IsDefault or matches defaultID.
+func SortAudioDevices(devices []AudioDevice, defaultID int) {
+	isDefault := func(d AudioDevice) bool {
+		return d.IsDefault || (defaultID != 0 && d.DeviceID == defaultID)
+	}
+
+	sort.SliceStable(devices, func(i, j int) bool {
+		a, b := devices[i], devices[j]
+		if isDefault(a) != isDefault(b) {
+			return isDefault(a)
+		}
+		if a.IsOnline != b.IsOnline {
+			return a.IsOnline
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
 // LoadPlugins loads plugin information using the standalone inspector tool
 func LoadPlugins() error {
-	log.Println("Loading plugin information...")
+	return LoadPluginsContext(context.Background())
+}
 
-	cmd := exec.Command("./standalone/inspector/inspector")
-	output, err := cmd.Output()
+// LoadPluginsContext is LoadPlugins with a context; cancelling ctx kills the
+// inspector tool and leaves the loaded plugin list untouched
+func LoadPluginsContext(ctx context.Context) error {
+	slog.Info("loading plugin information")
+
+	output, err := runTool(ctx, InspectorToolPath)
 	if err != nil {
-		return fmt.Errorf("failed to run inspector tool: %v", err)
+		return fmt.Errorf("failed to run inspector tool: %w", err)
 	}
 
-	err = json.Unmarshal(output, &Data.Plugins)
+	var plugins []Plugin
+	err = json.Unmarshal(output, &plugins)
 	if err != nil {
 		return fmt.Errorf("failed to parse plugins JSON: %v", err)
 	}
+	for i := range plugins {
+		plugins[i].Category = PluginCategory(plugins[i].Type)
+		for j := range plugins[i].Parameters {
+			param := &plugins[i].Parameters[j]
+			param.DefaultValue = RoundParameterValue(*param, param.DefaultValue)
+			param.CurrentValue = RoundParameterValue(*param, param.CurrentValue)
+		}
+	}
+
+	SetPlugins(plugins)
 
-	log.Printf("✅ Loaded %d AudioUnit plugins", len(Data.Plugins))
+	slog.Info("loaded AudioUnit plugins", "count", len(plugins))
 
 	return nil
 }
@@ -0,0 +1,306 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func deviceNames(devices []AudioDevice) []string {
+	names := make([]string, len(devices))
+	for i, device := range devices {
+		names[i] = device.Name
+	}
+	return names
+}
+
+// TestSortAudioDevicesDefaultFirst verifies the default device leads the list
+func TestSortAudioDevicesDefaultFirst(t *testing.T) {
+	devices := []AudioDevice{
+		{DeviceID: 81, Name: "Mac mini Speakers", IsOnline: true},
+		{DeviceID: 145, Name: "Steep II", IsOnline: true},
+		{DeviceID: 87, Name: "External Headphones", IsOnline: true},
+	}
+
+	SortAudioDevices(devices, 145)
+
+	if devices[0].DeviceID != 145 {
+		t.Fatalf("Expected default device 145 first, got %v", deviceNames(devices))
+	}
+	t.Logf("✅ Default device sorted first: %v", deviceNames(devices))
+}
+
+// TestSortAudioDevicesOrdering verifies default, then alphabetical, then offline ordering
+func TestSortAudioDevicesOrdering(t *testing.T) {
+	devices := []AudioDevice{
+		{DeviceID: 1, Name: "zoom", IsOnline: true},
+		{DeviceID: 2, Name: "Unplugged Interface", IsOnline: false},
+		{DeviceID: 3, Name: "Background Music", IsOnline: true},
+		{DeviceID: 4, Name: "KATANA", IsOnline: true, IsDefault: true},
+		{DeviceID: 5, Name: "Another Offline", IsOnline: false},
+		{DeviceID: 6, Name: "mac mini Speakers", IsOnline: true},
+	}
+
+	SortAudioDevices(devices, 0)
+
+	expected := []string{
+		"KATANA",
+		"Background Music",
+		"mac mini Speakers",
+		"zoom",
+		"Another Offline",
+		"Unplugged Interface",
+	}
+
+	got := deviceNames(devices)
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("Unexpected order:\n  got:  %v\n  want: %v", got, expected)
+		}
+	}
+	t.Logf("✅ Devices ordered correctly: %v", got)
+}
+
+// TestSortAudioDevicesEmpty verifies sorting an empty list is a no-op
+func TestSortAudioDevicesEmpty(t *testing.T) {
+	var devices []AudioDevice
+	SortAudioDevices(devices, 87)
+
+	if len(devices) != 0 {
+		t.Errorf("Expected empty list to stay empty, got %d devices", len(devices))
+	}
+}
+
+// TestParseDevicesDropsZeroChannelDevices verifies a device without input channels stays out of the input list
+func TestParseDevicesDropsZeroChannelDevices(t *testing.T) {
+	output := []byte(`{
+		"audioInput": [
+			{"deviceId": 145, "name": "Steep II", "channelCount": 2, "isOnline": true},
+			{"deviceId": 87, "name": "External Headphones", "channelCount": 0, "isOnline": true}
+		],
+		"audioOutput": [
+			{"deviceId": 87, "name": "External Headphones", "channelCount": 2, "isOnline": true, "isDefault": true}
+		],
+		"totalAudioInputDevices": 2,
+		"totalAudioOutputDevices": 1
+	}`)
+
+	devices, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+
+	if names := deviceNames(devices.AudioInput); len(names) != 1 || names[0] != "Steep II" {
+		t.Errorf("Expected only Steep II as input, got %v", names)
+	}
+	if devices.TotalAudioInputDevices != 1 {
+		t.Errorf("Expected input total 1, got %d", devices.TotalAudioInputDevices)
+	}
+	if len(devices.AudioOutput) != 1 {
+		t.Errorf("Expected headphones to remain an output, got %v", deviceNames(devices.AudioOutput))
+	}
+}
+
+func hashTestDevices() DevicesData {
+	return DevicesData{
+		AudioInput: []AudioDevice{
+			{DeviceID: 145, UID: "device_145", Name: "Steep II", ChannelCount: 2, IsOnline: true, SupportedSampleRates: []int{44100, 48000}},
+			{DeviceID: 105, UID: "device_105", Name: "KATANA", ChannelCount: 4, IsOnline: true, SupportedSampleRates: []int{48000}},
+		},
+		AudioOutput: []AudioDevice{
+			{DeviceID: 87, UID: "device_87", Name: "External Headphones", ChannelCount: 2, IsOnline: true, IsDefault: true},
+		},
+		MIDIInput: []MIDIDevice{{UID: "midi_744763039", Name: "KATANA", IsOnline: true}},
+		Defaults:  DefaultDevices{DefaultInput: 145, DefaultOutput: 87},
+		Timestamp: "2025-07-31 18:46:56 +0000",
+	}
+}
+
+// TestDevicesDataEqualIgnoresTimestamp verifies re-enumerating unchanged hardware compares equal
+func TestDevicesDataEqualIgnoresTimestamp(t *testing.T) {
+	a := hashTestDevices()
+	b := hashTestDevices()
+	b.Timestamp = "2025-07-31 19:00:00 +0000"
+	b.LoadedAt = time.Now()
+	b.Sequence = 42
+	b.AudioInput[0], b.AudioInput[1] = b.AudioInput[1], b.AudioInput[0]
+
+	if !a.Equal(b) {
+		t.Error("Expected results differing only in timestamp and order to be equal")
+	}
+	if a.Hash() != b.Hash() {
+		t.Errorf("Expected identical hashes, got %s and %s", a.Hash(), b.Hash())
+	}
+}
+
+// TestDevicesDataEqualDetectsDeviceChange verifies a changed device is detected
+func TestDevicesDataEqualDetectsDeviceChange(t *testing.T) {
+	a := hashTestDevices()
+	b := hashTestDevices()
+	b.AudioInput[1].IsOnline = false
+
+	if a.Equal(b) {
+		t.Error("Expected results with an offline device to differ")
+	}
+	if a.Hash() == b.Hash() {
+		t.Error("Expected different hashes")
+	}
+
+	hogged := hashTestDevices()
+	hogged.AudioInput[0].HogModePID = 4242
+	if a.Hash() == hogged.Hash() {
+		t.Error("Expected a hog mode change to alter the hash")
+	}
+
+	c := hashTestDevices()
+	c.AudioInput = c.AudioInput[:1]
+	if a.Equal(c) {
+		t.Error("Expected results with a removed device to differ")
+	}
+}
+
+// TestParseDevicesPartialEnumeration verifies audio devices survive a failed MIDI category
+func TestParseDevicesPartialEnumeration(t *testing.T) {
+	output := []byte(`{
+		"audioInput": [{"deviceId": 145, "name": "Steep II", "channelCount": 2, "isOnline": true}],
+		"audioOutput": [{"deviceId": 87, "name": "External Headphones", "channelCount": 2, "isOnline": true}],
+		"errors": {"midiInput": "MIDIGetNumberOfSources failed"}
+	}`)
+
+	devices, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+	if devices.Complete() {
+		t.Error("Expected enumeration to be reported incomplete")
+	}
+	if devices.Errors["midiInput"] == "" {
+		t.Errorf("Expected a midiInput error, got %v", devices.Errors)
+	}
+	if len(devices.AudioInput) != 1 || len(devices.AudioOutput) != 1 {
+		t.Errorf("Expected audio devices despite the MIDI failure, got %d inputs and %d outputs",
+			len(devices.AudioInput), len(devices.AudioOutput))
+	}
+}
+
+// TestParseDevicesSequenceIncreases verifies each enumeration is stamped in order
+func TestParseDevicesSequenceIncreases(t *testing.T) {
+	output := []byte(`{"audioOutput": [{"deviceId": 87, "name": "External Headphones", "channelCount": 2}]}`)
+
+	first, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+	second, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+
+	if second.Sequence <= first.Sequence {
+		t.Errorf("Expected increasing sequence numbers, got %d then %d", first.Sequence, second.Sequence)
+	}
+	if first.LoadedAt.IsZero() || second.LoadedAt.Before(first.LoadedAt) {
+		t.Errorf("Expected ordered load times, got %v then %v", first.LoadedAt, second.LoadedAt)
+	}
+	if !first.Equal(second) {
+		t.Error("Expected enumeration metadata to be ignored by Equal")
+	}
+}
+
+// TestParseDevicesExpandsSampleRateRanges verifies a continuous-range device lists the standard rates it covers
+func TestParseDevicesExpandsSampleRateRanges(t *testing.T) {
+	output := []byte(`{"audioOutput": [{
+		"deviceId": 87, "name": "Range Device", "channelCount": 2,
+		"supportedSampleRates": [44100],
+		"sampleRateRanges": [[32000, 32000], [44100, 44100], [44100, 192000]]
+	}]}`)
+
+	devices, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+
+	got := devices.AudioOutput[0].SupportedSampleRates
+	want := []int{32000, 44100, 48000, 88200, 96000, 176400, 192000}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// defaultNames returns the names of devices flagged as default
+func defaultNames(devices []AudioDevice) []string {
+	var names []string
+	for _, device := range devices {
+		if device.IsDefault {
+			names = append(names, device.Name)
+		}
+	}
+	return names
+}
+
+// TestParseDevicesReconcilesDefault verifies IsDefault follows Defaults and only one device claims it
+func TestParseDevicesReconcilesDefault(t *testing.T) {
+	output := []byte(`{
+		"audioInput": [
+			{"deviceId": 145, "name": "Steep II", "channelCount": 2, "isDefault": true},
+			{"deviceId": 105, "name": "KATANA", "channelCount": 4, "isDefault": true}
+		],
+		"audioOutput": [
+			{"deviceId": 87, "name": "External Headphones", "channelCount": 2},
+			{"deviceId": 145, "name": "Steep II", "channelCount": 2}
+		],
+		"defaults": {"defaultInput": 105, "defaultOutput": 87}
+	}`)
+
+	devices, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+	if got := defaultNames(devices.AudioInput); !slices.Equal(got, []string{"KATANA"}) {
+		t.Errorf("Expected only KATANA as default input, got %v", got)
+	}
+	if got := defaultNames(devices.AudioOutput); !slices.Equal(got, []string{"External Headphones"}) {
+		t.Errorf("Expected External Headphones as default output, got %v", got)
+	}
+
+	// Without a reported default, conflicting flags collapse to one
+	devices, err = parseDevices([]byte(`{"audioInput": [
+		{"deviceId": 145, "name": "Steep II", "channelCount": 2, "isDefault": true},
+		{"deviceId": 105, "name": "KATANA", "channelCount": 4, "isDefault": true}
+	]}`))
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+	if got := defaultNames(devices.AudioInput); len(got) != 1 {
+		t.Errorf("Expected exactly one default input, got %v", got)
+	}
+}
+
+// TestParseDevicesStreamFormat verifies the stream format is decoded and stays nil when unreported
+func TestParseDevicesStreamFormat(t *testing.T) {
+	output := []byte(`{"audioOutput": [
+		{"deviceId": 87, "name": "External Headphones", "channelCount": 2,
+		 "streamFormat": {"sampleFormat": "float32", "bitsPerChannel": 32, "interleaved": false}},
+		{"deviceId": 145, "name": "Steep II", "channelCount": 2, "streamFormat": null},
+		{"deviceId": 105, "name": "KATANA", "channelCount": 4}
+	]}`)
+
+	devices, err := parseDevices(output)
+	if err != nil {
+		t.Fatalf("Failed to parse devices: %v", err)
+	}
+
+	for _, device := range devices.AudioOutput {
+		switch device.DeviceID {
+		case 87:
+			want := StreamFormat{SampleFormat: "float32", BitsPerChannel: 32, Interleaved: false}
+			if device.StreamFormat == nil || *device.StreamFormat != want {
+				t.Errorf("Expected %+v, got %+v", want, device.StreamFormat)
+			}
+		default:
+			if device.StreamFormat != nil {
+				t.Errorf("%s: expected no stream format, got %+v", device.Name, device.StreamFormat)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package audio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Devices returns the loaded device data. Reloads replace the device lists
+// instead of mutating them, so the snapshot stays safe to read after the lock
+// is released.
+func Devices() DevicesData {
+	Mutex.RLock()
+	defer Mutex.RUnlock()
+	return Data.Devices
+}
+
+// deviceKey holds the fields of an audio device that matter for change detection
+type deviceKey struct {
+	UID          string `json:"uid"`
+	DeviceID     int    `json:"deviceId"`
+	Name         string `json:"name"`
+	ChannelCount int    `json:"channelCount"`
+	SampleRates  []int  `json:"sampleRates"`
+	IsDefault    bool   `json:"isDefault"`
+	IsOnline     bool   `json:"isOnline"`
+}
+
+// midiKey holds the fields of a MIDI endpoint that matter for change detection
+type midiKey struct {
+	UID      string `json:"uid"`
+	Name     string `json:"name"`
+	IsOnline bool   `json:"isOnline"`
+}
+
+// devicesFingerprint is an order-independent view of DevicesData without the
+// enumeration timestamp
+type devicesFingerprint struct {
+	AudioInput        []deviceKey    `json:"audioInput"`
+	AudioOutput       []deviceKey    `json:"audioOutput"`
+	MIDIInput         []midiKey      `json:"midiInput"`
+	MIDIOutput        []midiKey      `json:"midiOutput"`
+	Defaults          DefaultDevices `json:"defaults"`
+	DefaultSampleRate float64        `json:"defaultSampleRate"`
+}
+
+func (d DevicesData) fingerprint() devicesFingerprint {
+	audioKeys := func(devices []AudioDevice) []deviceKey {
+		keys := make([]deviceKey, len(devices))
+		for i, device := range devices {
+			rates := device.SupportedSampleRates
+			if len(rates) == 0 {
+				rates = nil
+			}
+			keys[i] = deviceKey{
+				UID:          device.UID,
+				DeviceID:     device.DeviceID,
+				Name:         device.Name,
+				ChannelCount: device.ChannelCount,
+				SampleRates:  rates,
+				IsDefault:    device.IsDefault,
+				IsOnline:     device.IsOnline,
+			}
+		}
+		slices.SortFunc(keys, func(a, b deviceKey) int { return strings.Compare(a.UID, b.UID) })
+		return keys
+	}
+	midiKeys := func(devices []MIDIDevice) []midiKey {
+		keys := make([]midiKey, len(devices))
+		for i, device := range devices {
+			keys[i] = midiKey{UID: device.UID, Name: device.Name, IsOnline: device.IsOnline}
+		}
+		slices.SortFunc(keys, func(a, b midiKey) int { return strings.Compare(a.UID, b.UID) })
+		return keys
+	}
+
+	return devicesFingerprint{
+		AudioInput:        audioKeys(d.AudioInput),
+		AudioOutput:       audioKeys(d.AudioOutput),
+		MIDIInput:         midiKeys(d.MIDIInput),
+		MIDIOutput:        midiKeys(d.MIDIOutput),
+		Defaults:          d.Defaults,
+		DefaultSampleRate: d.DefaultSampleRate,
+	}
+}
+
+// Hash returns a stable content hash of the device set. It covers every
+// reported field but ignores when and in what order the devices were
+// enumerated (Timestamp, LoadedAt, Sequence and list order), so
+// re-enumerating unchanged hardware yields the same hash.
+func (d DevicesData) Hash() string {
+	d.Timestamp, d.LoadedAt, d.Sequence = "", time.Time{}, 0
+	d.AudioInput = sortedByUID(d.AudioInput, func(device AudioDevice) string { return device.UID })
+	d.AudioOutput = sortedByUID(d.AudioOutput, func(device AudioDevice) string { return device.UID })
+	d.MIDIInput = sortedByUID(d.MIDIInput, func(device MIDIDevice) string { return device.UID })
+	d.MIDIOutput = sortedByUID(d.MIDIOutput, func(device MIDIDevice) string { return device.UID })
+
+	data, _ := json.Marshal(d)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// sortedByUID returns a copy of devices ordered by UID
+func sortedByUID[T any](devices []T, uid func(T) string) []T {
+	sorted := slices.Clone(devices)
+	slices.SortFunc(sorted, func(a, b T) int { return strings.Compare(uid(a), uid(b)) })
+	return sorted
+}
+
+// Equal reports whether two device sets contain the same devices, matched by
+// UID and compared on their key fields
+func (d DevicesData) Equal(other DevicesData) bool {
+	return reflect.DeepEqual(d.fingerprint(), other.fingerprint())
+}
+
+// Complete reports whether every device category enumerated successfully.
+// Errors maps each failed category (e.g. "midiInput") to its failure; the
+// categories that succeeded are still populated.
+func (d DevicesData) Complete() bool {
+	return len(d.Errors) == 0
+}
+
+// DeviceCounts is the number of devices per category plus the system
+// defaults, for clients that don't need the full device details
+type DeviceCounts struct {
+	AudioInput  int            `json:"audioInput"`
+	AudioOutput int            `json:"audioOutput"`
+	MIDIInput   int            `json:"midiInput"`
+	MIDIOutput  int            `json:"midiOutput"`
+	Defaults    DefaultDevices `json:"defaults"`
+	Sequence    uint64         `json:"sequence"`
+}
+
+// Counts summarizes the device set without copying any device details
+func (d DevicesData) Counts() DeviceCounts {
+	return DeviceCounts{
+		AudioInput:  len(d.AudioInput),
+		AudioOutput: len(d.AudioOutput),
+		MIDIInput:   len(d.MIDIInput),
+		MIDIOutput:  len(d.MIDIOutput),
+		Defaults:    d.Defaults,
+		Sequence:    d.Sequence,
+	}
+}
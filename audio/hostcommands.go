@@ -0,0 +1,89 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// parseCommandList reads a "COMMANDS: start stop status ..." response
+func parseCommandList(response string) ([]string, error) {
+	list, ok := strings.CutPrefix(strings.TrimSpace(response), "COMMANDS:")
+	if !ok {
+		return nil, fmt.Errorf("unexpected commands response: %q", response)
+	}
+	return strings.Fields(list), nil
+}
+
+// Audio-host binaries that could not list their commands, by path, with the
+// modification time they had. Starting the same binary again skips the query,
+// which against a build that never answers would cost a full command timeout.
+var (
+	legacyHostsMu sync.Mutex
+	legacyHosts   = map[string]time.Time{}
+)
+
+// ResetHostCommands forgets which audio-host binaries could not list their
+// commands, so the next start asks again
+func ResetHostCommands() {
+	legacyHostsMu.Lock()
+	defer legacyHostsMu.Unlock()
+	clear(legacyHosts)
+}
+
+// queryCommands asks audio-host which commands it accepts. Builds that predate
+// the query answer with an error, which leaves the command list unknown and is
+// remembered for hostPath until the binary changes.
+func (p *AudioHostProcess) queryCommands(hostPath string) {
+	info, statErr := os.Stat(hostPath)
+	if statErr == nil {
+		legacyHostsMu.Lock()
+		modTime, known := legacyHosts[hostPath]
+		legacyHostsMu.Unlock()
+		if known && modTime.Equal(info.ModTime()) {
+			slog.Debug("audio-host cannot list its commands, skipping the query", "path", hostPath)
+			return
+		}
+	}
+
+	response, err := p.SendCommand("commands")
+	if err == nil {
+		var commands []string
+		if commands, err = parseCommandList(response); err == nil {
+			p.mu.Lock()
+			p.commands = commands
+			p.mu.Unlock()
+			slog.Debug("audio-host commands", "commands", commands)
+			return
+		}
+	}
+	slog.Info("audio-host did not report its commands, assuming all are supported", "err", err)
+
+	if statErr == nil {
+		legacyHostsMu.Lock()
+		legacyHosts[hostPath] = info.ModTime()
+		legacyHostsMu.Unlock()
+	}
+}
+
+// Commands returns the commands audio-host reported at startup. ok is false
+// when the host could not list them.
+func (p *AudioHostProcess) Commands() (commands []string, ok bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.commands == nil {
+		return nil, false
+	}
+	return slices.Clone(p.commands), true
+}
+
+// Supports reports whether audio-host accepts command. A host that could not
+// list its commands is assumed to accept everything.
+func (p *AudioHostProcess) Supports(command string) bool {
+	commands, ok := p.Commands()
+	return !ok || slices.Contains(commands, command)
+}
@@ -0,0 +1,34 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseCommandList verifies the one-line command list is split into commands
+func TestParseCommandList(t *testing.T) {
+	commands, err := parseCommandList("COMMANDS: start stop status tone\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := []string{"start", "stop", "status", "tone"}; !slices.Equal(commands, want) {
+		t.Errorf("Expected %v, got %v", want, commands)
+	}
+
+	if _, err := parseCommandList("ERROR: unknown command 'commands' (try 'help')"); err == nil {
+		t.Error("Expected an error for a host without the commands query")
+	}
+}
+
+// TestSupportsUnknownCommandList verifies a host that couldn't list its commands is assumed to accept all
+func TestSupportsUnknownCommandList(t *testing.T) {
+	legacy := &AudioHostProcess{}
+	if !legacy.Supports("monitor") {
+		t.Error("Expected an unknown command list to accept everything")
+	}
+
+	current := &AudioHostProcess{commands: []string{"start", "stop"}}
+	if !current.Supports("start") || current.Supports("monitor") {
+		t.Error("Expected Supports to follow the reported command list")
+	}
+}
@@ -0,0 +1,105 @@
+package audio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LastConfigPath is where the last successfully started configuration is kept,
+// relative to the data directory
+const LastConfigPath = "data/last-audio-config.json"
+
+// SavedAudioConfig is the persisted form of the last running configuration.
+// Device IDs are not stable across reboots, so the input device UID is stored
+// alongside the config and used to find the device again.
+type SavedAudioConfig struct {
+	Config         AudioConfig `json:"config"`
+	InputDeviceUID string      `json:"inputDeviceUID,omitempty"`
+	SavedAt        time.Time   `json:"savedAt"`
+}
+
+// SaveLastConfig persists config as the configuration to resume after a restart
+func SaveLastConfig(config AudioConfig) error {
+	saved := SavedAudioConfig{Config: config, SavedAt: time.Now().UTC()}
+	if config.AudioInputDeviceID != 0 {
+		for _, device := range Devices().AudioInput {
+			if device.DeviceID == config.AudioInputDeviceID {
+				saved.InputDeviceUID = device.UID
+				break
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode audio config: %v", err)
+	}
+
+	path := ResolvePath(LastConfigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write audio config: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to save audio config: %v", err)
+	}
+	return nil
+}
+
+// LoadLastConfig reads the persisted configuration. It returns nil without an
+// error when nothing has been saved yet.
+func LoadLastConfig() (*SavedAudioConfig, error) {
+	data, err := os.ReadFile(ResolvePath(LastConfigPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved audio config: %v", err)
+	}
+
+	var saved SavedAudioConfig
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse saved audio config: %v", err)
+	}
+	return &saved, nil
+}
+
+// Reconcile matches the saved configuration against the live devices and
+// returns a config usable now, with the input device ID refreshed from its UID.
+// It fails if the saved input device is gone or offline.
+func (s SavedAudioConfig) Reconcile(devices DevicesData) (AudioConfig, error) {
+	config := s.Config
+	if config.AudioInputDeviceID == 0 {
+		return config, nil
+	}
+
+	for _, device := range devices.AudioInput {
+		matched := device.DeviceID == config.AudioInputDeviceID
+		if s.InputDeviceUID != "" {
+			matched = device.UID == s.InputDeviceUID
+		}
+		if !matched {
+			continue
+		}
+		if !device.IsOnline {
+			return config, fmt.Errorf("saved input device %s is offline", device.Name)
+		}
+		config.AudioInputDeviceID = device.DeviceID
+		return config, nil
+	}
+
+	if s.InputDeviceUID != "" {
+		return config, fmt.Errorf("saved input device %s is no longer available", s.InputDeviceUID)
+	}
+	return config, fmt.Errorf("saved input device %d is no longer available", config.AudioInputDeviceID)
+}
@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"testing"
+)
+
+// TestSaveLastConfigRoundTrip verifies a saved config is read back with its input UID
+func TestSaveLastConfigRoundTrip(t *testing.T) {
+	useDataDir(t, t.TempDir())
+
+	original := Data.Devices
+	defer func() { Data.Devices = original }()
+	Data.Devices = DevicesData{
+		AudioInput: []AudioDevice{{DeviceID: 145, UID: "device_145", Name: "Steep II", IsOnline: true}},
+	}
+
+	config := AudioConfig{SampleRate: 96000, BufferSize: 256, AudioInputDeviceID: 145, AudioInputChannel: 1}
+	if err := SaveLastConfig(config); err != nil {
+		t.Fatalf("SaveLastConfig failed: %v", err)
+	}
+
+	saved, err := LoadLastConfig()
+	if err != nil || saved == nil {
+		t.Fatalf("LoadLastConfig failed: %v", err)
+	}
+	if saved.Config != config {
+		t.Errorf("Expected %+v, got %+v", config, saved.Config)
+	}
+	if saved.InputDeviceUID != "device_145" {
+		t.Errorf("Expected input UID device_145, got %q", saved.InputDeviceUID)
+	}
+}
+
+// TestLoadLastConfigMissing verifies a fresh data directory has no saved config
+func TestLoadLastConfigMissing(t *testing.T) {
+	useDataDir(t, t.TempDir())
+
+	saved, err := LoadLastConfig()
+	if err != nil || saved != nil {
+		t.Errorf("Expected no saved config and no error, got %+v, %v", saved, err)
+	}
+}
+
+// TestReconcileSavedConfig verifies device IDs are refreshed by UID and missing devices are rejected
+func TestReconcileSavedConfig(t *testing.T) {
+	saved := SavedAudioConfig{
+		Config:         AudioConfig{SampleRate: 48000, AudioInputDeviceID: 145},
+		InputDeviceUID: "device_145",
+	}
+
+	// Same device, new ID after a reboot
+	devices := DevicesData{AudioInput: []AudioDevice{{DeviceID: 151, UID: "device_145", Name: "Steep II", IsOnline: true}}}
+	config, err := saved.Reconcile(devices)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if config.AudioInputDeviceID != 151 {
+		t.Errorf("Expected input device ID refreshed to 151, got %d", config.AudioInputDeviceID)
+	}
+
+	devices.AudioInput[0].IsOnline = false
+	if _, err := saved.Reconcile(devices); err == nil {
+		t.Error("Expected offline input device to be rejected")
+	}
+
+	if _, err := saved.Reconcile(DevicesData{}); err == nil {
+		t.Error("Expected missing input device to be rejected")
+	}
+}
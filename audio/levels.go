@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InputLevelMinInterval throttles get-input-level round trips to audio-host.
+// Requests arriving sooner reuse the previous reading.
+var InputLevelMinInterval = 50 * time.Millisecond
+
+// InputLevel is a snapshot of the input signal level in dBFS
+type InputLevel struct {
+	PeakDB    float64   `json:"peakDb"`
+	RMSDB     float64   `json:"rmsDb"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// parseInputLevel parses a "LEVEL: peak=-12.3 rms=-20.1" response line
+func parseInputLevel(line string) (InputLevel, error) {
+	fields, ok := strings.CutPrefix(strings.TrimSpace(line), "LEVEL:")
+	if !ok {
+		return InputLevel{}, fmt.Errorf("unexpected input level response: %q", line)
+	}
+
+	var level InputLevel
+	var sawPeak, sawRMS bool
+	for _, field := range strings.Fields(fields) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			continue
+		}
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return InputLevel{}, fmt.Errorf("invalid %s value %q: %v", key, value, err)
+		}
+		switch key {
+		case "peak":
+			level.PeakDB, sawPeak = number, true
+		case "rms":
+			level.RMSDB, sawRMS = number, true
+		}
+	}
+
+	if !sawPeak || !sawRMS {
+		return InputLevel{}, fmt.Errorf("incomplete input level response: %q", line)
+	}
+	return level, nil
+}
+
+// InputLevel returns the current input level from audio-host, reusing the
+// previous reading if it is younger than InputLevelMinInterval
+func (p *AudioHostProcess) InputLevel() (InputLevel, error) {
+	p.levelMu.Lock()
+	defer p.levelMu.Unlock()
+
+	if !p.lastLevelAt.IsZero() && time.Since(p.lastLevelAt) < InputLevelMinInterval {
+		return p.lastLevel, nil
+	}
+
+	response, err := p.SendCommand("get-input-level")
+	if err != nil {
+		return InputLevel{}, err
+	}
+
+	level, err := parseInputLevel(response)
+	if err != nil {
+		return InputLevel{}, err
+	}
+	level.Timestamp = time.Now()
+
+	p.lastLevel = level
+	p.lastLevelAt = level.Timestamp
+	return level, nil
+}
+
+// ResetInputLevel discards the reused input level so the next call queries audio-host
+func (p *AudioHostProcess) ResetInputLevel() {
+	p.levelMu.Lock()
+	defer p.levelMu.Unlock()
+	p.lastLevel = InputLevel{}
+	p.lastLevelAt = time.Time{}
+}
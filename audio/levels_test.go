@@ -0,0 +1,29 @@
+package audio
+
+import (
+	"testing"
+)
+
+// TestParseInputLevel verifies LEVEL responses are parsed into dBFS values
+func TestParseInputLevel(t *testing.T) {
+	level, err := parseInputLevel("LEVEL: peak=-12.3 rms=-20.1")
+	if err != nil {
+		t.Fatalf("parseInputLevel failed: %v", err)
+	}
+	if level.PeakDB != -12.3 || level.RMSDB != -20.1 {
+		t.Errorf("Unexpected level: %+v", level)
+	}
+}
+
+// TestParseInputLevelInvalid verifies malformed responses are rejected
+func TestParseInputLevelInvalid(t *testing.T) {
+	for _, line := range []string{
+		"ERROR: unknown command 'get-input-level' (try 'help')",
+		"LEVEL: peak=-12.3",
+		"LEVEL: peak=loud rms=-20.1",
+	} {
+		if _, err := parseInputLevel(line); err == nil {
+			t.Errorf("Expected %q to be rejected", line)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LifecycleState describes where the audio-host process is in its lifecycle
+type LifecycleState int
+
+const (
+	StateStopped LifecycleState = iota
+	StateStarting
+	StateRunning
+	StateStopping
+	StateSwitching
+)
+
+func (s LifecycleState) String() string {
+	switch s {
+	case StateStopped:
+		return "stopped"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	case StateSwitching:
+		return "switching"
+	default:
+		return "unknown"
+	}
+}
+
+// InTransition reports whether a start, stop or switch is in flight
+func (s LifecycleState) InTransition() bool {
+	return s == StateStarting || s == StateStopping || s == StateSwitching
+}
+
+// LifecycleRetryAfter is how long clients are told to wait before retrying a
+// lifecycle request rejected because another one is in flight
+const LifecycleRetryAfter = 1 * time.Second
+
+// LifecycleBusyError is returned when a lifecycle request arrives while
+// another transition is still in flight
+type LifecycleBusyError struct {
+	State LifecycleState
+}
+
+func (e *LifecycleBusyError) Error() string {
+	return fmt.Sprintf("audio engine is %s, retry once it settles", e.State)
+}
+
+// LifecycleGuard serializes start, stop and switch requests so that a request
+// arriving mid-transition cannot race the one already running
+type LifecycleGuard struct {
+	mu    sync.Mutex
+	state LifecycleState
+}
+
+// Lifecycle guards the global audio-host process
+var Lifecycle = &LifecycleGuard{}
+
+// State returns the current lifecycle state. Outside a transition it follows
+// the global process, so a crashed audio-host reads as stopped.
+func (g *LifecycleGuard) State() LifecycleState {
+	g.mu.Lock()
+	state := g.state
+	g.mu.Unlock()
+
+	if state.InTransition() {
+		return state
+	}
+	return processState()
+}
+
+// Begin enters the given transition, failing with a LifecycleBusyError when
+// another transition is already in flight. Every successful Begin must be
+// paired with End.
+func (g *LifecycleGuard) Begin(transition LifecycleState) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.state.InTransition() {
+		return &LifecycleBusyError{State: g.state}
+	}
+	g.state = transition
+	return nil
+}
+
+// End leaves the current transition, settling on Running or Stopped depending
+// on whether the global audio-host process is alive
+func (g *LifecycleGuard) End() {
+	state := processState()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.state = state
+}
+
+// processState derives the settled state from the global audio-host process
+func processState() LifecycleState {
+	Mutex.RLock()
+	defer Mutex.RUnlock()
+	if Process != nil && Process.IsRunning() {
+		return StateRunning
+	}
+	return StateStopped
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLifecycleRejectsOverlappingTransitions verifies a second transition fails until End
+func TestLifecycleRejectsOverlappingTransitions(t *testing.T) {
+	g := &LifecycleGuard{}
+
+	if err := g.Begin(StateSwitching); err != nil {
+		t.Fatalf("Expected first transition to succeed, got: %v", err)
+	}
+	if state := g.State(); state != StateSwitching {
+		t.Errorf("Expected switching, got %s", state)
+	}
+
+	err := g.Begin(StateStarting)
+	var busy *LifecycleBusyError
+	if !errors.As(err, &busy) || busy.State != StateSwitching {
+		t.Fatalf("Expected busy error naming switching, got: %v", err)
+	}
+
+	g.End()
+	if state := g.State(); state != StateStopped {
+		t.Errorf("Expected stopped without a process, got %s", state)
+	}
+	if err := g.Begin(StateStarting); err != nil {
+		t.Errorf("Expected transition after End to succeed, got: %v", err)
+	}
+}
+
+// TestLifecycleStateStrings verifies the names reported to clients
+func TestLifecycleStateStrings(t *testing.T) {
+	expected := map[LifecycleState]string{
+		StateStopped:   "stopped",
+		StateStarting:  "starting",
+		StateRunning:   "running",
+		StateStopping:  "stopping",
+		StateSwitching: "switching",
+	}
+	for state, name := range expected {
+		if state.String() != name {
+			t.Errorf("Expected %q, got %q", name, state.String())
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotSupported is returned for operations the current build has no backend for
+var ErrNotSupported = errors.New("not supported")
+
+// Validate checks the request fields are within MIDI ranges
+func (r MIDISendRequest) Validate() error {
+	if r.DeviceUID == "" {
+		return fmt.Errorf("deviceUID is required")
+	}
+	if r.Channel == 0 {
+		// Channels are 1-based, so zero means the field was left out
+		return fmt.Errorf("channel is required (1-16)")
+	}
+	if r.Channel < 1 || r.Channel > 16 {
+		return fmt.Errorf("invalid channel %d (must be 1-16)", r.Channel)
+	}
+	switch r.Type {
+	case "", MIDIControlChange:
+		if r.CC < 0 || r.CC > 127 {
+			return fmt.Errorf("invalid cc %d (must be 0-127)", r.CC)
+		}
+	case MIDIProgramChange:
+	default:
+		return fmt.Errorf("unknown message type %q (expected %s or %s)", r.Type, MIDIControlChange, MIDIProgramChange)
+	}
+	if r.Value < 0 || r.Value > 127 {
+		return fmt.Errorf("invalid value %d (must be 0-127)", r.Value)
+	}
+	return nil
+}
+
+// Bytes encodes the request as a raw MIDI channel message
+func (r MIDISendRequest) Bytes() []byte {
+	status := byte(r.Channel-1) & 0x0F
+	if r.Type == MIDIProgramChange {
+		return []byte{0xC0 | status, byte(r.Value)}
+	}
+	return []byte{0xB0 | status, byte(r.CC), byte(r.Value)}
+}
+
+// FindMIDIOutput returns the MIDI output device with the given UID
+func FindMIDIOutput(devices DevicesData, uid string) (MIDIDevice, bool) {
+	for _, device := range devices.MIDIOutput {
+		if device.UID == uid {
+			return device, true
+		}
+	}
+	return MIDIDevice{}, false
+}
+
+// SendMIDI sends a message to a MIDI output endpoint. None of the standalone
+// tools can open MIDI outputs yet, so this always returns ErrNotSupported.
+func SendMIDI(request MIDISendRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	return fmt.Errorf("MIDI output to %s: %w", request.DeviceUID, ErrNotSupported)
+}
+
+// DefaultMIDIClockWindow is how long a clock probe listens when no window is given
+const DefaultMIDIClockWindow = 500 * time.Millisecond
+
+// MIDI system real-time status bytes
+const (
+	midiTimingClock = 0xF8
+	midiStart       = 0xFA
+	midiContinue    = 0xFB
+	midiStop        = 0xFC
+)
+
+// MIDIClockStatus reports what a MIDI input sent during a probe window
+type MIDIClockStatus struct {
+	DeviceUID        string `json:"deviceUID"`
+	ClockDetected    bool   `json:"clockDetected"`
+	TransportRunning bool   `json:"transportRunning"`
+	WindowMs         int64  `json:"windowMs"`
+}
+
+// AnalyzeMIDIClock scans raw MIDI bytes for timing clock and the last transport message
+func AnalyzeMIDIClock(data []byte) (clockDetected, transportRunning bool) {
+	for _, b := range data {
+		switch b {
+		case midiTimingClock:
+			clockDetected = true
+		case midiStart, midiContinue:
+			transportRunning = true
+		case midiStop:
+			transportRunning = false
+		}
+	}
+	return clockDetected, transportRunning
+}
+
+// FindMIDIInput returns the MIDI input device with the given UID
+func FindMIDIInput(devices DevicesData, uid string) (MIDIDevice, bool) {
+	for _, device := range devices.MIDIInput {
+		if device.UID == uid {
+			return device, true
+		}
+	}
+	return MIDIDevice{}, false
+}
+
+// ProbeMIDIClock listens on a MIDI input for window and reports whether clock
+// was seen. Probing opens the port, so it only runs on request and never as
+// part of device enumeration. None of the standalone tools can open MIDI
+// inputs yet, so this always returns ErrNotSupported.
+func ProbeMIDIClock(device MIDIDevice, window time.Duration) (MIDIClockStatus, error) {
+	status := MIDIClockStatus{DeviceUID: device.UID, WindowMs: window.Milliseconds()}
+	return status, fmt.Errorf("MIDI clock probe on %s: %w", device.UID, ErrNotSupported)
+}
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestMIDISendRequestValidate verifies out-of-range fields are rejected
+func TestMIDISendRequestValidate(t *testing.T) {
+	valid := MIDISendRequest{DeviceUID: "midi_1", Channel: 1, CC: 74, Value: 100}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected valid request, got: %v", err)
+	}
+
+	invalid := []MIDISendRequest{
+		{Channel: 1, CC: 74, Value: 100},
+		{DeviceUID: "midi_1", Channel: 0, CC: 74, Value: 100},
+		{DeviceUID: "midi_1", Channel: 17, CC: 74, Value: 100},
+		{DeviceUID: "midi_1", Channel: 1, CC: 128, Value: 100},
+		{DeviceUID: "midi_1", Channel: 1, CC: 74, Value: 128},
+		{DeviceUID: "midi_1", Channel: 1, Type: "sysex"},
+	}
+	for _, request := range invalid {
+		if err := request.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", request)
+		}
+	}
+}
+
+// TestMIDISendRequestChannelMessages verifies a missing channel is reported
+// as missing rather than out of range
+func TestMIDISendRequestChannelMessages(t *testing.T) {
+	cases := []struct {
+		channel int
+		want    string
+	}{
+		{0, "channel is required (1-16)"},
+		{17, "invalid channel 17 (must be 1-16)"},
+		{-1, "invalid channel -1 (must be 1-16)"},
+	}
+	for _, c := range cases {
+		err := MIDISendRequest{DeviceUID: "midi_1", Channel: c.channel, CC: 74, Value: 100}.Validate()
+		if err == nil || err.Error() != c.want {
+			t.Errorf("Channel %d: expected %q, got %v", c.channel, c.want, err)
+		}
+	}
+	if err := (MIDISendRequest{DeviceUID: "midi_1", Channel: 16, CC: 74}).Validate(); err != nil {
+		t.Errorf("Expected channel 16 to be valid, got: %v", err)
+	}
+}
+
+// TestMIDISendRequestBytes verifies CC and program change encoding
+func TestMIDISendRequestBytes(t *testing.T) {
+	cc := MIDISendRequest{DeviceUID: "midi_1", Channel: 2, CC: 7, Value: 100}
+	if got := cc.Bytes(); !bytes.Equal(got, []byte{0xB1, 7, 100}) {
+		t.Errorf("Unexpected CC bytes: % X", got)
+	}
+
+	program := MIDISendRequest{DeviceUID: "midi_1", Type: MIDIProgramChange, Channel: 16, Value: 5}
+	if got := program.Bytes(); !bytes.Equal(got, []byte{0xCF, 5}) {
+		t.Errorf("Unexpected program change bytes: % X", got)
+	}
+}
+
+// TestSendMIDINotSupported verifies the stub reports ErrNotSupported for valid requests
+func TestSendMIDINotSupported(t *testing.T) {
+	err := SendMIDI(MIDISendRequest{DeviceUID: "midi_1", Channel: 1, CC: 74, Value: 100})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got: %v", err)
+	}
+}
+
+// TestAnalyzeMIDIClock verifies clock and transport detection from raw bytes
+func TestAnalyzeMIDIClock(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    []byte
+		clock   bool
+		running bool
+	}{
+		{"silence", nil, false, false},
+		{"notes only", []byte{0x90, 60, 100, 0x80, 60, 0}, false, false},
+		{"clock while stopped", []byte{0xF8, 0xF8, 0xF8}, true, false},
+		{"clock after start", []byte{0xFA, 0xF8, 0xF8}, true, true},
+		{"stopped after continue", []byte{0xFB, 0xF8, 0xFC, 0xF8}, true, false},
+	}
+
+	for _, tc := range cases {
+		clock, running := AnalyzeMIDIClock(tc.data)
+		if clock != tc.clock || running != tc.running {
+			t.Errorf("%s: expected clock=%v running=%v, got clock=%v running=%v",
+				tc.name, tc.clock, tc.running, clock, running)
+		}
+	}
+}
+
+// TestProbeMIDIClockNotSupported verifies the stub probe reports ErrNotSupported
+func TestProbeMIDIClockNotSupported(t *testing.T) {
+	status, err := ProbeMIDIClock(MIDIDevice{UID: "midi_1"}, DefaultMIDIClockWindow)
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Expected ErrNotSupported, got: %v", err)
+	}
+	if status.DeviceUID != "midi_1" || status.WindowMs != 500 {
+		t.Errorf("Unexpected probe status: %+v", status)
+	}
+}
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParameterPrecision returns the number of decimals a parameter's values are
+// kept to: enough to resolve a thousandth of its range, whole steps for
+// indexed and boolean parameters, and two decimals when the range is unknown
+func ParameterPrecision(param PluginParameter) int {
+	if len(param.IndexedValues) > 0 || param.Unit == "Indexed" || param.Unit == "Boolean" {
+		return 0
+	}
+	span := param.MaxValue - param.MinValue
+	if span <= 0 || math.IsNaN(span) || math.IsInf(span, 0) {
+		return 2
+	}
+	return min(max(3-int(math.Ceil(math.Log10(span))), 0), 6)
+}
+
+// RoundParameterValue rounds value to the parameter's precision, removing
+// float noise such as 0.30000000000000004 and snapping stepped parameters
+// to whole steps
+func RoundParameterValue(param PluginParameter, value float64) float64 {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return value
+	}
+	scale := math.Pow10(ParameterPrecision(param))
+	rounded := math.Round(value*scale) / scale
+	if rounded == 0 {
+		return 0 // drop negative zero
+	}
+	return rounded
+}
+
+// FormatParameterValue renders a parameter value for display using the
+// parameter's indexed labels or unit, so server and UI format values identically
+func FormatParameterValue(param PluginParameter, value float64) string {
+	value = RoundParameterValue(param, value)
+	if len(param.IndexedValues) > 0 {
+		if label, ok := IndexedLabel(param, value); ok {
+			return label
+		}
+		return strconv.Itoa(int(math.Round(value)))
+	}
+
+	switch param.Unit {
+	case "Boolean":
+		if value >= 0.5 {
+			return "On"
+		}
+		return "Off"
+	case "Hertz":
+		if math.Abs(value) >= 1000 {
+			return fmt.Sprintf("%.2f kHz", value/1000)
+		}
+		return fmt.Sprintf("%.0f Hz", value)
+	case "Decibels":
+		return fmt.Sprintf("%.1f dB", value)
+	case "Percent":
+		return fmt.Sprintf("%.0f%%", value)
+	case "Milliseconds":
+		return fmt.Sprintf("%.0f ms", value)
+	case "Seconds":
+		return fmt.Sprintf("%.2f s", value)
+	case "Cents":
+		return fmt.Sprintf("%.0f ct", value)
+	case "Degrees":
+		return fmt.Sprintf("%.0f°", value)
+	case "Relative Semitones":
+		return fmt.Sprintf("%.0f st", value)
+	case "Octaves":
+		return fmt.Sprintf("%.2f oct", value)
+	case "Ratio":
+		return fmt.Sprintf("%.1f:1", value)
+	case "Meters":
+		return fmt.Sprintf("%.1f m", value)
+	default:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
+
+// IndexedLabel returns the label for the step nearest to value on an indexed
+// parameter. It reports false for non-indexed parameters and out-of-range steps.
+func IndexedLabel(param PluginParameter, value float64) (string, bool) {
+	if len(param.IndexedValues) == 0 || math.IsNaN(value) {
+		return "", false
+	}
+
+	// Indexed parameters may start at a non-zero minimum
+	index := int(math.Round(value - param.MinValue))
+	if index < 0 || index >= len(param.IndexedValues) {
+		return "", false
+	}
+	return param.IndexedValues[index], true
+}
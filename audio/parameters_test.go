@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"testing"
+)
+
+// TestFormatParameterValueIndexed verifies indexed parameters show their labels
+func TestFormatParameterValueIndexed(t *testing.T) {
+	param := PluginParameter{
+		DisplayName:   "Waveform",
+		Unit:          "Indexed",
+		IndexedValues: []string{"Sin", "Inv. Sin", "Peak", "Inv. Peak"},
+	}
+
+	if got := FormatParameterValue(param, 2); got != "Peak" {
+		t.Errorf("Expected %q for index 2, got %q", "Peak", got)
+	}
+	if got := FormatParameterValue(param, 0.6); got != "Inv. Sin" {
+		t.Errorf("Expected fractional value to round to %q, got %q", "Inv. Sin", got)
+	}
+}
+
+// TestFormatParameterValueUnits verifies unit suffixes for common AudioUnit units
+func TestFormatParameterValueUnits(t *testing.T) {
+	cases := []struct {
+		unit     string
+		value    float64
+		expected string
+	}{
+		{"Hertz", 440, "440 Hz"},
+		{"Hertz", 2500, "2.50 kHz"},
+		{"Decibels", -6, "-6.0 dB"},
+		{"Percent", 50, "50%"},
+		{"Milliseconds", 20, "20 ms"},
+		{"Seconds", 1.5, "1.50 s"},
+		{"Boolean", 1, "On"},
+		{"Boolean", 0, "Off"},
+	}
+
+	for _, tc := range cases {
+		param := PluginParameter{Unit: tc.unit}
+		if got := FormatParameterValue(param, tc.value); got != tc.expected {
+			t.Errorf("%s %v: expected %q, got %q", tc.unit, tc.value, tc.expected, got)
+		}
+	}
+}
+
+// TestFormatParameterValuePlain verifies unitless parameters render as plain floats
+func TestFormatParameterValuePlain(t *testing.T) {
+	param := PluginParameter{Unit: "Generic"}
+
+	if got := FormatParameterValue(param, 0.25); got != "0.25" {
+		t.Errorf("Expected %q, got %q", "0.25", got)
+	}
+}
+
+// TestRoundParameterValue verifies noisy floats are cleaned to the range's precision and steps are aligned
+func TestRoundParameterValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		param    PluginParameter
+		value    float64
+		expected float64
+	}{
+		{"float noise in unit range", PluginParameter{MaxValue: 1}, 0.1 + 0.2, 0.3},
+		{"thousandth of unit range", PluginParameter{MaxValue: 1}, 0.12345, 0.123},
+		{"wide range keeps whole numbers", PluginParameter{MinValue: 20, MaxValue: 20000}, 440.0000001, 440},
+		{"decibel range", PluginParameter{MinValue: -96, MaxValue: 24}, -6.00000000001, -6},
+		{"indexed snaps to step", PluginParameter{MaxValue: 3, IndexedValues: []string{"a", "b", "c", "d"}}, 1.6, 2},
+		{"boolean snaps to step", PluginParameter{Unit: "Boolean", MaxValue: 1}, 0.9999, 1},
+		{"unknown range", PluginParameter{}, 0.30000000000000004, 0.3},
+		{"negative zero", PluginParameter{MaxValue: 1}, -0.0001, 0},
+	}
+
+	for _, tc := range cases {
+		if got := RoundParameterValue(tc.param, tc.value); got != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, got)
+		}
+	}
+}
+
+// TestFormatParameterValueNoisyFloat verifies float noise never reaches the display
+func TestFormatParameterValueNoisyFloat(t *testing.T) {
+	param := PluginParameter{Unit: "Generic", MaxValue: 1}
+
+	if got := FormatParameterValue(param, 0.1+0.2); got != "0.3" {
+		t.Errorf("Expected %q, got %q", "0.3", got)
+	}
+}
+
+// TestIndexedLabel verifies values map to the label of the nearest step
+func TestIndexedLabel(t *testing.T) {
+	param := PluginParameter{
+		Unit:          "Indexed",
+		MaxValue:      2,
+		IndexedValues: []string{"Off", "Low", "High"},
+	}
+
+	for value, expected := range map[float64]string{0: "Off", 1: "Low", 1.4: "Low", 1.6: "High", 2: "High"} {
+		label, ok := IndexedLabel(param, value)
+		if !ok || label != expected {
+			t.Errorf("Value %v: expected %q, got %q (ok=%v)", value, expected, label, ok)
+		}
+	}
+
+	// Indexed ranges that start above zero are offset by MinValue
+	offset := PluginParameter{MinValue: 1, MaxValue: 3, IndexedValues: []string{"33", "45", "78"}}
+	if label, ok := IndexedLabel(offset, 3); !ok || label != "78" {
+		t.Errorf("Expected %q for offset index 3, got %q (ok=%v)", "78", label, ok)
+	}
+}
+
+// TestIndexedLabelOutOfRange verifies out-of-range and non-indexed values are rejected
+func TestIndexedLabelOutOfRange(t *testing.T) {
+	param := PluginParameter{IndexedValues: []string{"Off", "Low", "High"}}
+
+	for _, value := range []float64{-1, 3, 42} {
+		if label, ok := IndexedLabel(param, value); ok {
+			t.Errorf("Value %v: expected no label, got %q", value, label)
+		}
+	}
+	if got := FormatParameterValue(param, 5); got != "5" {
+		t.Errorf("Expected out-of-range value to fall back to %q, got %q", "5", got)
+	}
+	if _, ok := IndexedLabel(PluginParameter{Unit: "Decibels"}, 0); ok {
+		t.Error("Expected non-indexed parameter to have no label")
+	}
+}
@@ -0,0 +1,70 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Locations of the standalone tools and data files, relative to the data directory
+const (
+	DevicesToolPath   = "standalone/devices/devices"
+	InspectorToolPath = "standalone/inspector/inspector"
+	AudioHostToolPath = "standalone/audio-host/audio-host"
+	StaticFilesPath   = "frontend/static"
+)
+
+// DataDirEnv is the environment variable that overrides the default data directory
+const DataDirEnv = "RACKLESS_DATA_DIR"
+
+// AudioHostPathEnv is the environment variable that points at a specific audio-host binary
+const AudioHostPathEnv = "RACKLESS_AUDIOHOST_PATH"
+
+// DefaultDataDir returns the data directory to use when none is given explicitly.
+// RACKLESS_DATA_DIR wins if set; otherwise the executable's directory is used
+// when it contains the standalone tools, falling back to the working directory
+// (which is what `go run .` needs, since its binary lives in a temp dir).
+func DefaultDataDir() string {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return dir
+	}
+
+	if executable, err := os.Executable(); err == nil {
+		dir := filepath.Dir(executable)
+		if info, err := os.Stat(filepath.Join(dir, "standalone")); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+
+	return "."
+}
+
+// ResolvePath resolves a path relative to the configured data directory
+func ResolvePath(relative string) string {
+	if filepath.IsAbs(relative) {
+		return relative
+	}
+
+	dir := DataDir
+	if dir == "" {
+		dir = "."
+	}
+	// Keep a leading "./" so exec never falls back to a $PATH lookup
+	path := filepath.Join(dir, relative)
+	if !filepath.IsAbs(path) {
+		path = "." + string(filepath.Separator) + path
+	}
+	return path
+}
+
+// AudioHostExecutable returns the audio-host binary to launch. The first of
+// these that is set wins: RACKLESS_AUDIOHOST_PATH, the AudioHostPath setting,
+// then the standard location under the data directory.
+func AudioHostExecutable() string {
+	if path := os.Getenv(AudioHostPathEnv); path != "" {
+		return path
+	}
+	if AudioHostPath != "" {
+		return AudioHostPath
+	}
+	return ResolvePath(AudioHostToolPath)
+}
@@ -0,0 +1,155 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFakeTool installs an executable shell script at the given path under dir
+func writeFakeTool(t *testing.T, dir, relative, script string) {
+	t.Helper()
+	path := filepath.Join(dir, relative)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create tool directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write fake tool: %v", err)
+	}
+}
+
+// useDataDir points the audio package at dir for the duration of the test
+func useDataDir(t *testing.T, dir string) {
+	t.Helper()
+	original := DataDir
+	DataDir = dir
+	t.Cleanup(func() { DataDir = original })
+}
+
+// TestResolvePath verifies paths resolve against the configured data directory
+func TestResolvePath(t *testing.T) {
+	useDataDir(t, "/opt/rackless")
+	if got := ResolvePath(DevicesToolPath); got != "/opt/rackless/standalone/devices/devices" {
+		t.Errorf("Unexpected resolved path: %s", got)
+	}
+
+	DataDir = ""
+	if got := ResolvePath(AudioHostToolPath); got != "./standalone/audio-host/audio-host" {
+		t.Errorf("Expected working-directory relative path, got %s", got)
+	}
+
+	if got := ResolvePath("/usr/local/bin/audio-host"); got != "/usr/local/bin/audio-host" {
+		t.Errorf("Expected absolute path to be kept, got %s", got)
+	}
+}
+
+// TestDefaultDataDirEnvOverride verifies RACKLESS_DATA_DIR takes precedence
+func TestDefaultDataDirEnvOverride(t *testing.T) {
+	t.Setenv(DataDirEnv, "/srv/rackless")
+	if got := DefaultDataDir(); got != "/srv/rackless" {
+		t.Errorf("Expected env override, got %s", got)
+	}
+}
+
+// TestLoadDevicesFromDataDir verifies the devices tool is run from an explicit data directory
+func TestLoadDevicesFromDataDir(t *testing.T) {
+	dir := t.TempDir()
+	useDataDir(t, dir)
+	writeFakeTool(t, dir, DevicesToolPath, `cat <<'JSON'
+{
+  "audioInput": [{"deviceId": 145, "uid": "device_145", "name": "Steep II", "channelCount": 2, "isOnline": true, "supportedSampleRates": [48000]}],
+  "audioOutput": [{"deviceId": 87, "uid": "device_87", "name": "External Headphones", "channelCount": 2, "isOnline": true, "supportedSampleRates": [48000]}],
+  "defaults": {"defaultInput": 145, "defaultOutput": 87},
+  "totalAudioInputDevices": 1,
+  "totalAudioOutputDevices": 1,
+  "defaultSampleRate": 48000
+}
+JSON
+`)
+
+	original := Data.Devices
+	defer func() { Data.Devices = original }()
+
+	if err := LoadDevices(); err != nil {
+		t.Fatalf("LoadDevices failed: %v", err)
+	}
+
+	if len(Data.Devices.AudioOutput) != 1 || Data.Devices.AudioOutput[0].DeviceID != 87 {
+		t.Fatalf("Expected devices from data dir tool, got %+v", Data.Devices.AudioOutput)
+	}
+	t.Logf("✅ Loaded devices from %s", dir)
+}
+
+// TestLoadDevicesTimeout verifies a hung devices tool fails with a timeout error
+func TestLoadDevicesTimeout(t *testing.T) {
+	dir := t.TempDir()
+	useDataDir(t, dir)
+	writeFakeTool(t, dir, DevicesToolPath, "sleep 5\n")
+
+	originalTimeout := ToolTimeout
+	ToolTimeout = 100 * time.Millisecond
+	defer func() { ToolTimeout = originalTimeout }()
+
+	start := time.Now()
+	err := LoadDevices()
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Expected timeout error from hung devices tool")
+	}
+	if !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected a timeout error, got: %v", err)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("LoadDevices took %v, expected it to give up near the timeout", elapsed)
+	}
+	t.Logf("✅ Hung tool aborted after %v: %v", elapsed, err)
+}
+
+// TestLoadDevicesContextCancelled verifies cancelling the context kills the devices tool
+func TestLoadDevicesContextCancelled(t *testing.T) {
+	dir := t.TempDir()
+	useDataDir(t, dir)
+	writeFakeTool(t, dir, DevicesToolPath, "sleep 5\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := LoadDevicesContext(ctx)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the context error, got: %v", err)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("LoadDevicesContext took %v, expected it to stop on cancellation", elapsed)
+	}
+}
+
+// TestAudioHostExecutablePrecedence verifies env beats the setting, which beats the data directory
+func TestAudioHostExecutablePrecedence(t *testing.T) {
+	useDataDir(t, "/opt/rackless")
+	original := AudioHostPath
+	defer func() { AudioHostPath = original }()
+
+	t.Setenv(AudioHostPathEnv, "")
+	AudioHostPath = ""
+	if got := AudioHostExecutable(); got != "/opt/rackless/standalone/audio-host/audio-host" {
+		t.Errorf("Expected data directory binary, got %s", got)
+	}
+
+	AudioHostPath = "/usr/local/bin/audio-host"
+	if got := AudioHostExecutable(); got != "/usr/local/bin/audio-host" {
+		t.Errorf("Expected configured binary, got %s", got)
+	}
+
+	t.Setenv(AudioHostPathEnv, "/tmp/audio-host-dev")
+	if got := AudioHostExecutable(); got != "/tmp/audio-host-dev" {
+		t.Errorf("Expected env override to win, got %s", got)
+	}
+}
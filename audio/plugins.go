@@ -0,0 +1,74 @@
+package audio
+
+// PluginSummary is a plugin without its parameter list, for lightweight listings
+type PluginSummary struct {
+	ManufacturerID string `json:"manufacturerID"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Subtype        string `json:"subtype"`
+	Category       string `json:"category"`
+	ParameterCount int    `json:"parameterCount"`
+}
+
+// pluginCategories maps AudioUnit component type codes, as the inspector
+// reports them, to the category the UI groups plugins by. The inspector scans
+// every component type, so codes such as augn only appear once a generator is
+// installed; aunt is the private type Apple's SystemOutputUnit registers under.
+var pluginCategories = map[string]string{
+	"aufx": "Effect",
+	"aumf": "Music Effect",
+	"aumu": "Instrument",
+	"aumi": "MIDI Processor",
+	"augn": "Generator",
+	"aufc": "Format Converter",
+	"aumx": "Mixer",
+	"aupn": "Panner",
+	"auou": "Output",
+	"aunt": "Output",
+	"auol": "Offline Effect",
+}
+
+// PluginCategory returns a human-readable category for an AudioUnit type code,
+// or "Other" for codes it does not know
+func PluginCategory(typeCode string) string {
+	if category, ok := pluginCategories[typeCode]; ok {
+		return category
+	}
+	return "Other"
+}
+
+// Summary returns the plugin's identity and parameter count
+func (p Plugin) Summary() PluginSummary {
+	return PluginSummary{
+		ManufacturerID: p.ManufacturerID,
+		Name:           p.Name,
+		Type:           p.Type,
+		Subtype:        p.Subtype,
+		Category:       p.Category,
+		ParameterCount: len(p.Parameters),
+	}
+}
+
+// SummarizePlugins returns summaries for a list of plugins
+func SummarizePlugins(plugins []Plugin) []PluginSummary {
+	summaries := make([]PluginSummary, len(plugins))
+	for i, plugin := range plugins {
+		summaries[i] = plugin.Summary()
+	}
+	return summaries
+}
+
+// Plugins returns the loaded plugin list. Reloads replace the slice instead of
+// mutating it, so the result stays safe to read after the lock is released.
+func Plugins() []Plugin {
+	Mutex.RLock()
+	defer Mutex.RUnlock()
+	return Data.Plugins
+}
+
+// SetPlugins replaces the loaded plugin list
+func SetPlugins(plugins []Plugin) {
+	Mutex.Lock()
+	defer Mutex.Unlock()
+	Data.Plugins = plugins
+}
@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestPluginSummary verifies summaries keep identity and count parameters
+func TestPluginSummary(t *testing.T) {
+	plugins := []Plugin{
+		{Name: "AUDelay", ManufacturerID: "appl", Type: "aufx", Subtype: "dely", Category: "Effect",
+			Parameters: []PluginParameter{{DisplayName: "Dry/Wet Mix"}, {DisplayName: "Delay Time"}}},
+		{Name: "AUDistortion", ManufacturerID: "appl", Type: "aufx", Subtype: "dist"},
+	}
+
+	summaries := SummarizePlugins(plugins)
+	if len(summaries) != 2 {
+		t.Fatalf("Expected 2 summaries, got %d", len(summaries))
+	}
+	expected := PluginSummary{ManufacturerID: "appl", Name: "AUDelay", Type: "aufx", Subtype: "dely", Category: "Effect", ParameterCount: 2}
+	if summaries[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, summaries[0])
+	}
+	if summaries[1].ParameterCount != 0 {
+		t.Errorf("Expected no parameters, got %d", summaries[1].ParameterCount)
+	}
+}
+
+// TestPluginCategory verifies AudioUnit type codes map to UI categories
+func TestPluginCategory(t *testing.T) {
+	cases := map[string]string{
+		"aufx": "Effect",
+		"aumf": "Music Effect",
+		"aumu": "Instrument",
+		"aumi": "MIDI Processor",
+		"augn": "Generator",
+		"auou": "Output",
+		"aunt": "Output",
+		"xxxx": "Other",
+		"":     "Other",
+	}
+	for typeCode, want := range cases {
+		if got := PluginCategory(typeCode); got != want {
+			t.Errorf("PluginCategory(%q) = %q, want %q", typeCode, got, want)
+		}
+	}
+}
+
+// TestPluginCategoryCoversInspectorSample verifies every type code in the
+// inspector's sample output has a category
+func TestPluginCategoryCoversInspectorSample(t *testing.T) {
+	raw, err := os.ReadFile("../standalone/inspector/plugins.json")
+	if err != nil {
+		t.Fatalf("Failed to read sample plugins: %v", err)
+	}
+	var plugins []Plugin
+	if err := json.Unmarshal(raw, &plugins); err != nil {
+		t.Fatalf("Failed to parse sample plugins: %v", err)
+	}
+	if len(plugins) == 0 {
+		t.Fatal("Expected sample plugins")
+	}
+	for _, plugin := range plugins {
+		if category := PluginCategory(plugin.Type); category == "Other" {
+			t.Errorf("Type %q (%s) has no category", plugin.Type, plugin.Name)
+		}
+	}
+}
+
+// TestSetPluginsReplacesList verifies the accessor pair round-trips the plugin list
+func TestSetPluginsReplacesList(t *testing.T) {
+	original := Plugins()
+	t.Cleanup(func() { SetPlugins(original) })
+
+	SetPlugins([]Plugin{{Name: "AUDelay"}})
+	if plugins := Plugins(); len(plugins) != 1 || plugins[0].Name != "AUDelay" {
+		t.Errorf("Expected the replaced list, got %+v", plugins)
+	}
+}
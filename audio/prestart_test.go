@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useDeviceEnumerator replaces the device enumerator for the duration of a test
+func useDeviceEnumerator(t *testing.T, devices DevicesData) {
+	t.Helper()
+	original := DeviceEnumerator
+	DeviceEnumerator = func() DevicesData { return devices }
+	t.Cleanup(func() { DeviceEnumerator = original })
+}
+
+func preStartDevices() DevicesData {
+	return DevicesData{
+		AudioInput: []AudioDevice{
+			{DeviceID: 145, Name: "Steep II", IsOnline: true, HogModePID: -1},
+			{DeviceID: 105, Name: "KATANA", IsOnline: true, HogModePID: 4242},
+			{DeviceID: 120, Name: "Old Interface", IsOnline: false, HogModePID: -1},
+		},
+		AudioOutput: []AudioDevice{
+			{DeviceID: 87, Name: "External Headphones", IsOnline: true, IsDefault: true, HogModePID: -1},
+		},
+	}
+}
+
+// TestPreStartCheckFreeDevices verifies free, online devices pass
+func TestPreStartCheckFreeDevices(t *testing.T) {
+	useDeviceEnumerator(t, preStartDevices())
+
+	if err := PreStartCheck(AudioConfig{SampleRate: 48000, AudioInputDeviceID: 145}); err != nil {
+		t.Errorf("Expected free devices to pass, got: %v", err)
+	}
+	if err := PreStartCheck(AudioConfig{SampleRate: 48000}); err != nil {
+		t.Errorf("Expected output-only config to pass, got: %v", err)
+	}
+}
+
+// TestPreStartCheckHoggedInput verifies a hogged input names the device and PID
+func TestPreStartCheckHoggedInput(t *testing.T) {
+	useDeviceEnumerator(t, preStartDevices())
+
+	err := PreStartCheck(AudioConfig{SampleRate: 48000, AudioInputDeviceID: 105})
+	var inUse *DeviceInUseError
+	if !errors.As(err, &inUse) {
+		t.Fatalf("Expected DeviceInUseError, got: %v", err)
+	}
+	if inUse.PID != 4242 {
+		t.Errorf("Expected PID 4242, got %d", inUse.PID)
+	}
+	if err.Error() != "Device KATANA is in use by PID 4242" {
+		t.Errorf("Unexpected message: %q", err.Error())
+	}
+}
+
+// TestPreStartCheckHoggedOutput verifies a hogged default output blocks the start
+func TestPreStartCheckHoggedOutput(t *testing.T) {
+	devices := preStartDevices()
+	devices.AudioOutput[0].HogModePID = 999
+	useDeviceEnumerator(t, devices)
+
+	err := PreStartCheck(AudioConfig{SampleRate: 48000})
+	if err == nil || !strings.Contains(err.Error(), "External Headphones is in use by PID 999") {
+		t.Errorf("Expected hogged output error, got: %v", err)
+	}
+}
+
+// TestPreStartCheckUnavailable verifies offline and unknown devices are reported
+func TestPreStartCheckUnavailable(t *testing.T) {
+	useDeviceEnumerator(t, preStartDevices())
+
+	err := PreStartCheck(AudioConfig{SampleRate: 48000, AudioInputDeviceID: 120})
+	if err == nil || !strings.Contains(err.Error(), "Old Interface is not available") {
+		t.Errorf("Expected offline device error, got: %v", err)
+	}
+	err = PreStartCheck(AudioConfig{SampleRate: 48000, AudioInputDeviceID: 999})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected missing device error, got: %v", err)
+	}
+}
+
+// TestIsHogged verifies free, legacy and own-process hog values
+func TestIsHogged(t *testing.T) {
+	for _, pid := range []int{-1, 0} {
+		if (AudioDevice{HogModePID: pid}).IsHogged() {
+			t.Errorf("Expected PID %d to mean free", pid)
+		}
+	}
+	if !(AudioDevice{HogModePID: 4242}).IsHogged() {
+		t.Error("Expected PID 4242 to mean hogged")
+	}
+}
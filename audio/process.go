@@ -3,8 +3,10 @@ package audio
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
 	"os/exec"
 	"strconv"
 	"strings"
@@ -29,13 +31,18 @@ func StartAudioHostProcess(config AudioConfig) (*AudioHostProcess, error) {
 		args = append(args, "--no-tone")
 	}
 
-	log.Printf("🚀 Starting: ./standalone/audio-host/audio-host %s", strings.Join(args, " "))
+	hostPath := AudioHostExecutable()
+	slog.Info("starting audio-host", "path", hostPath, "args", strings.Join(args, " "))
 
 	// Create context for process management
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Create command
-	cmd := exec.CommandContext(ctx, "./standalone/audio-host/audio-host", args...)
+	cmd := exec.CommandContext(ctx, hostPath, args...)
+	cmd.Dir = AudioHostDir
+	if len(AudioHostEnv) > 0 {
+		cmd.Env = append(os.Environ(), AudioHostEnv...)
+	}
 
 	// Set up pipes for bidirectional communication
 	stdin, err := cmd.StdinPipe()
@@ -77,10 +84,18 @@ func StartAudioHostProcess(config AudioConfig) (*AudioHostProcess, error) {
 		running: true,
 		ctx:     ctx,
 		cancel:  cancel,
+		exited:  make(chan struct{}),
+
+		responses: make(chan string, 16),
+		requested: config,
+	}
+	if CommandLogSize > 0 {
+		process.commandLog = NewCommandLog(CommandLogSize)
 	}
 
 	// Start goroutine to handle process exit
 	go process.handleProcessExit()
+	go process.readResponses()
 
 	// Wait for "READY" signal from audio-host
 	if err := process.waitForReady(); err != nil {
@@ -91,31 +106,88 @@ func StartAudioHostProcess(config AudioConfig) (*AudioHostProcess, error) {
 	// Now start the stderr handler for ongoing logging
 	go process.handleStderr()
 
-	log.Printf("✅ Audio-host started successfully with PID %d", process.pid)
+	// Learn which commands this audio-host build accepts
+	process.queryCommands(hostPath)
+
+	actual := process.ActualConfig()
+	if actual.SampleRate != config.SampleRate || actual.BufferSize != config.BufferSize {
+		slog.Warn("audio-host is not running at the requested configuration",
+			"requestedSampleRate", config.SampleRate, "sampleRate", actual.SampleRate,
+			"requestedBufferSize", config.BufferSize, "bufferSize", actual.BufferSize)
+	}
+
+	slog.Info("audio-host started", "pid", process.pid,
+		"sampleRate", actual.SampleRate, "bufferSize", actual.BufferSize)
 	return process, nil
 }
 
-// waitForReady waits for the READY signal from audio-host
+// DefaultReadySentinel is the startup marker audio-host prints on stderr
+const DefaultReadySentinel = "READY"
+
+// ReadySentinel is the marker waitForReady looks for. audio-host may follow it
+// with a JSON object describing the configuration it actually negotiated.
+var ReadySentinel = DefaultReadySentinel
+
+// ReadyInfo is the optional payload of a "READY {json}" line
+type ReadyInfo struct {
+	SampleRate float64 `json:"sampleRate,omitempty"`
+	BufferSize int     `json:"bufferSize,omitempty"`
+}
+
+// parseReadyLine reports whether line carries the ready sentinel and decodes
+// the JSON payload following it, if any
+func parseReadyLine(line, sentinel string) (bool, *ReadyInfo, error) {
+	index := strings.Index(line, sentinel)
+	if index < 0 {
+		return false, nil, nil
+	}
+
+	payload := strings.TrimSpace(line[index+len(sentinel):])
+	if !strings.HasPrefix(payload, "{") {
+		return true, nil, nil
+	}
+
+	var info ReadyInfo
+	if err := json.Unmarshal([]byte(payload), &info); err != nil {
+		return true, nil, fmt.Errorf("invalid ready payload %q: %v", payload, err)
+	}
+	return true, &info, nil
+}
+
+// waitForReady waits for the ready sentinel from audio-host and records any
+// negotiated configuration it reports
 func (p *AudioHostProcess) waitForReady() error {
-	// Read from stderr until we see "READY"
-	timeout := time.NewTimer(5 * time.Second)
+	// Read from stderr until we see the sentinel
+	limit := Timeouts.effective().Ready
+	timeout := time.NewTimer(limit)
 	defer timeout.Stop()
 
 	readyChan := make(chan bool, 1)
+	sentinel := ReadySentinel
 
-	// Start a goroutine to scan stderr for the READY signal
+	// Start a goroutine to scan stderr for the ready signal
 	go func() {
 		defer close(readyChan)
 		scanner := bufio.NewScanner(p.stderr)
 		for scanner.Scan() {
 			line := scanner.Text()
-			log.Printf("🎧 Audio-host stderr: %s", line)
-			if strings.Contains(line, "READY") {
-				readyChan <- true
-				return
+			slog.Debug("audio-host stderr", "line", line)
+			ready, info, err := parseReadyLine(line, sentinel)
+			if !ready {
+				continue
+			}
+			if err != nil {
+				slog.Warn("ignoring audio-host ready payload", "err", err)
 			}
+			if info != nil {
+				p.mu.Lock()
+				p.readyInfo = info
+				p.mu.Unlock()
+			}
+			readyChan <- true
+			return
 		}
-		// If scanner exits without finding READY, send false
+		// If scanner exits without finding the sentinel, send false
 		readyChan <- false
 	}()
 
@@ -124,10 +196,52 @@ func (p *AudioHostProcess) waitForReady() error {
 		if ready {
 			return nil
 		}
-		return fmt.Errorf("audio-host exited without sending READY signal")
+		return fmt.Errorf("audio-host exited without sending %s signal", sentinel)
 	case <-timeout.C:
-		return fmt.Errorf("timeout waiting for READY signal from audio-host")
+		return fmt.Errorf("timeout waiting %v for %s signal from audio-host", limit, sentinel)
+	}
+}
+
+// Apply returns config with the values audio-host reported in place of the
+// requested ones
+func (info ReadyInfo) Apply(config AudioConfig) AudioConfig {
+	if info.SampleRate > 0 {
+		config.SampleRate = info.SampleRate
+	}
+	if info.BufferSize > 0 {
+		config.BufferSize = info.BufferSize
+	}
+	return config
+}
+
+// RequestedConfig returns the configuration the process was started with
+func (p *AudioHostProcess) RequestedConfig() AudioConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.requested
+}
+
+// ActualConfig returns the configuration audio-host is really running at. It
+// differs from RequestedConfig when the host clamped the sample rate or buffer
+// size; hosts that send a bare READY are assumed to honor the request.
+func (p *AudioHostProcess) ActualConfig() AudioConfig {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.readyInfo == nil {
+		return p.requested
 	}
+	return p.readyInfo.Apply(p.requested)
+}
+
+// ReadyInfo returns the configuration audio-host reported with its ready
+// signal, or false when it sent a bare sentinel
+func (p *AudioHostProcess) ReadyInfo() (ReadyInfo, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.readyInfo == nil {
+		return ReadyInfo{}, false
+	}
+	return *p.readyInfo, true
 }
 
 // handleStderr continuously reads and logs stderr output
@@ -135,59 +249,103 @@ func (p *AudioHostProcess) handleStderr() {
 	scanner := bufio.NewScanner(p.stderr)
 	for scanner.Scan() {
 		line := scanner.Text()
-		log.Printf("🎧 Audio-host: %s", line)
+		slog.Debug("audio-host stderr", "pid", p.pid, "line", line)
 	}
 }
 
 // handleProcessExit handles process cleanup when it exits
 func (p *AudioHostProcess) handleProcessExit() {
 	p.cmd.Wait()
+	close(p.exited)
 	p.mu.Lock()
 	p.running = false
 	p.mu.Unlock()
-	log.Printf("🔇 Audio-host process (PID %d) has exited", p.pid)
+	slog.Info("audio-host process exited", "pid", p.pid)
 }
 
 // SendCommand sends a command to the audio-host process and returns the response
 func (p *AudioHostProcess) SendCommand(command string) (string, error) {
+	if p.commandLog == nil {
+		return p.sendCommand(command)
+	}
+
+	sentAt := time.Now()
+	response, err := p.sendCommand(command)
+	entry := CommandLogEntry{Command: command, Response: response, SentAt: sentAt, Duration: time.Since(sentAt)}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	p.commandLog.Record(entry)
+	return response, err
+}
+
+// sendCommand writes one command line and reads one response line
+func (p *AudioHostProcess) sendCommand(command string) (string, error) {
 	p.mu.RLock()
 	if !p.running {
 		p.mu.RUnlock()
 		return "", fmt.Errorf("audio-host process is not running")
 	}
 	stdin := p.stdin
-	stdout := p.stdout
 	p.mu.RUnlock()
 
+	p.cmdMu.Lock()
+	defer p.cmdMu.Unlock()
+	if err := p.discardStale(); err != nil {
+		return "", err
+	}
+
 	// Send command
 	_, err := fmt.Fprintf(stdin, "%s\n", command)
 	if err != nil {
 		return "", fmt.Errorf("failed to send command: %v", err)
 	}
 
-	// Read response with timeout
-	respChan := make(chan string, 1)
-	errChan := make(chan error, 1)
+	return p.readResponse()
+}
 
-	go func() {
-		scanner := bufio.NewScanner(stdout)
-		if scanner.Scan() {
-			respChan <- scanner.Text()
-		} else {
-			errChan <- fmt.Errorf("failed to read response")
-		}
-	}()
+// readResponses forwards stdout to the responses channel one line at a time.
+// A single reader for the process's lifetime means a line that arrives after
+// its command timed out is still there to be discarded, rather than being
+// picked up by whichever read happens to be waiting.
+func (p *AudioHostProcess) readResponses() {
+	defer close(p.responses)
+	scanner := bufio.NewScanner(p.stdout)
+	for scanner.Scan() {
+		p.responses <- scanner.Text()
+	}
+}
 
+// readResponse waits for the next response line. On timeout the response is
+// still owed, so it is counted as stale for the next command to skip.
+// Callers hold cmdMu.
+func (p *AudioHostProcess) readResponse() (string, error) {
 	select {
-	case response := <-respChan:
+	case response, ok := <-p.responses:
+		if !ok {
+			return "", fmt.Errorf("failed to read response")
+		}
 		return response, nil
-	case err := <-errChan:
-		return "", err
-	case <-time.After(5 * time.Second):
+	case <-time.After(Timeouts.effective().Command):
+		p.stale++
 		return "", fmt.Errorf("timeout waiting for response")
 	}
 }
 
+// discardStale skips the late responses to commands that timed out, so the
+// next command reads its own answer. Callers hold cmdMu.
+func (p *AudioHostProcess) discardStale() error {
+	for p.stale > 0 {
+		p.stale--
+		response, err := p.readResponse()
+		if err != nil {
+			return fmt.Errorf("audio-host still hasn't answered an earlier command: %w", err)
+		}
+		slog.Debug("discarding late audio-host response", "pid", p.GetPID(), "response", response)
+	}
+	return nil
+}
+
 // Stop gracefully stops the audio-host process
 func (p *AudioHostProcess) Stop() error {
 	p.mu.Lock()
@@ -206,16 +364,12 @@ func (p *AudioHostProcess) Stop() error {
 	// Cancel context to kill process if needed
 	p.cancel()
 
-	// Wait for process to exit (with timeout)
-	done := make(chan error, 1)
-	go func() {
-		done <- p.cmd.Wait()
-	}()
-
+	// Wait for handleProcessExit to reap the process (with timeout);
+	// cmd.Wait may only be called once
 	select {
-	case <-done:
+	case <-p.exited:
 		// Process exited gracefully
-	case <-time.After(3 * time.Second):
+	case <-time.After(Timeouts.effective().Stop):
 		// Force kill if it doesn't exit
 		if p.cmd.Process != nil {
 			p.cmd.Process.Kill()
@@ -231,7 +385,7 @@ func (p *AudioHostProcess) Stop() error {
 	}
 
 	p.running = false
-	log.Printf("🔇 Audio-host process stopped")
+	slog.Info("audio-host process stopped", "pid", p.pid)
 	return nil
 }
 
@@ -0,0 +1,48 @@
+package audio
+
+import "testing"
+
+// TestParseReadyLine verifies bare, structured and custom ready sentinels
+func TestParseReadyLine(t *testing.T) {
+	ready, info, err := parseReadyLine("READY", DefaultReadySentinel)
+	if !ready || info != nil || err != nil {
+		t.Errorf("Bare READY: got ready=%v info=%v err=%v", ready, info, err)
+	}
+
+	ready, info, err = parseReadyLine(`READY {"sampleRate":48000,"bufferSize":128}`, DefaultReadySentinel)
+	if !ready || err != nil || info == nil {
+		t.Fatalf("Structured READY: got ready=%v info=%v err=%v", ready, info, err)
+	}
+	if info.SampleRate != 48000 || info.BufferSize != 128 {
+		t.Errorf("Expected 48000/128, got %+v", info)
+	}
+
+	if ready, _, _ := parseReadyLine("Initializing engine", DefaultReadySentinel); ready {
+		t.Error("Expected log line without sentinel to be ignored")
+	}
+
+	if ready, _, _ := parseReadyLine("HOST-UP", "HOST-UP"); !ready {
+		t.Error("Expected custom sentinel to match")
+	}
+
+	// A malformed payload still counts as ready so startup isn't blocked
+	ready, info, err = parseReadyLine("READY {broken", DefaultReadySentinel)
+	if !ready || info != nil || err == nil {
+		t.Errorf("Malformed payload: got ready=%v info=%v err=%v", ready, info, err)
+	}
+}
+
+// TestReadyInfoApply verifies reported values replace requested ones and zero values are ignored
+func TestReadyInfoApply(t *testing.T) {
+	requested := AudioConfig{SampleRate: 48000, BufferSize: 128, AudioInputDeviceID: 145}
+
+	actual := ReadyInfo{SampleRate: 44100}.Apply(requested)
+	if actual.SampleRate != 44100 || actual.BufferSize != 128 || actual.AudioInputDeviceID != 145 {
+		t.Errorf("Unexpected actual config: %+v", actual)
+	}
+
+	p := &AudioHostProcess{requested: requested}
+	if p.ActualConfig() != requested {
+		t.Errorf("Expected requested config without a ready payload, got %+v", p.ActualConfig())
+	}
+}
@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// HostCheckTTL is how long an audio-host binary check is reused before the
+// file is inspected again
+const HostCheckTTL = 30 * time.Second
+
+// HostBinaryStatus reports whether the audio-host binary can be launched
+type HostBinaryStatus struct {
+	Path      string    `json:"path"`
+	Runnable  bool      `json:"runnable"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+var (
+	hostCheckMu sync.Mutex
+	hostCheck   HostBinaryStatus
+)
+
+// CheckAudioHostBinary reports whether the configured audio-host binary exists
+// and is executable. Results are cached for HostCheckTTL per path so frequent
+// readiness probes don't hit the filesystem each time.
+func CheckAudioHostBinary() HostBinaryStatus {
+	path := AudioHostExecutable()
+
+	hostCheckMu.Lock()
+	defer hostCheckMu.Unlock()
+	if hostCheck.Path == path && time.Since(hostCheck.CheckedAt) < HostCheckTTL {
+		return hostCheck
+	}
+
+	hostCheck = HostBinaryStatus{Path: path, CheckedAt: time.Now()}
+	if err := checkExecutable(path); err != nil {
+		hostCheck.Error = err.Error()
+	} else {
+		hostCheck.Runnable = true
+	}
+	return hostCheck
+}
+
+func checkExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("audio-host not found at %s", path)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("audio-host at %s is not a regular file", path)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		return fmt.Errorf("audio-host at %s is not executable", path)
+	}
+	return nil
+}
+
+// ResetHostCheck discards the cached audio-host binary check so the next call
+// inspects the file again
+func ResetHostCheck() {
+	hostCheckMu.Lock()
+	defer hostCheckMu.Unlock()
+	hostCheck = HostBinaryStatus{}
+}
@@ -0,0 +1,55 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCheckAudioHostBinary verifies present, missing and non-executable binaries are told apart
+func TestCheckAudioHostBinary(t *testing.T) {
+	present := t.TempDir()
+	useDataDir(t, present)
+	writeFakeTool(t, present, AudioHostToolPath, "exit 0\n")
+	if status := CheckAudioHostBinary(); !status.Runnable {
+		t.Errorf("Expected an executable audio-host to be runnable, got: %s", status.Error)
+	}
+
+	missing := t.TempDir()
+	useDataDir(t, missing)
+	if status := CheckAudioHostBinary(); status.Runnable || status.Error == "" {
+		t.Errorf("Expected a missing audio-host to be reported, got %+v", status)
+	}
+
+	notExecutable := t.TempDir()
+	useDataDir(t, notExecutable)
+	path := filepath.Join(notExecutable, AudioHostToolPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("not a program"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if status := CheckAudioHostBinary(); status.Runnable {
+		t.Error("Expected a non-executable audio-host to be reported")
+	}
+}
+
+// TestResetHostCheck verifies a reset forces the binary to be inspected again
+func TestResetHostCheck(t *testing.T) {
+	dir := t.TempDir()
+	useDataDir(t, dir)
+	if status := CheckAudioHostBinary(); status.Runnable {
+		t.Fatal("Expected no audio-host yet")
+	}
+
+	writeFakeTool(t, dir, AudioHostToolPath, "exit 0\n")
+	if status := CheckAudioHostBinary(); status.Runnable {
+		t.Fatal("Expected the cached result before a reset")
+	}
+
+	ResetHostCheck()
+	if status := CheckAudioHostBinary(); !status.Runnable {
+		t.Errorf("Expected a fresh check after reset, got: %s", status.Error)
+	}
+}
@@ -0,0 +1,120 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// SampleRate is an audio sample rate in Hz
+type SampleRate int
+
+// StandardSampleRates lists the common rates in order of preference
+var StandardSampleRates = []SampleRate{44100, 48000, 96000, 192000}
+
+// NominalSampleRates lists the rates a continuous range reported by CoreAudio
+// is expanded into, in ascending order
+var NominalSampleRates = []int{8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000, 176400, 192000, 352800, 384000}
+
+// ExpandSampleRateRanges converts [min, max] rate ranges into a sorted list of
+// discrete rates: a range with min == max is that rate, a wider range yields
+// the nominal rates it covers
+func ExpandSampleRateRanges(ranges [][2]int) []int {
+	var rates []int
+	for _, r := range ranges {
+		if r[0] == r[1] {
+			rates = append(rates, r[0])
+			continue
+		}
+		for _, rate := range NominalSampleRates {
+			if rate >= r[0] && rate <= r[1] {
+				rates = append(rates, rate)
+			}
+		}
+	}
+	slices.Sort(rates)
+	return slices.Compact(rates)
+}
+
+// IsStandard reports whether r is one of the standard rates
+func (r SampleRate) IsStandard() bool {
+	for _, standard := range StandardSampleRates {
+		if r == standard {
+			return true
+		}
+	}
+	return false
+}
+
+// Nyquist returns the highest frequency representable at this rate
+func (r SampleRate) Nyquist() float64 {
+	return float64(r) / 2
+}
+
+// SupportedBy reports whether r appears in a device's supported rates
+func (r SampleRate) SupportedBy(rates []int) bool {
+	for _, rate := range rates {
+		if SampleRate(rate) == r {
+			return true
+		}
+	}
+	return false
+}
+
+// NearestSupported returns the supported rate closest to r, preferring the
+// higher rate on a tie so nothing is lost to downsampling. It reports false
+// when rates is empty.
+func (r SampleRate) NearestSupported(rates []int) (SampleRate, bool) {
+	if len(rates) == 0 {
+		return 0, false
+	}
+
+	best := SampleRate(rates[0])
+	for _, rate := range rates[1:] {
+		candidate := SampleRate(rate)
+		distance, bestDistance := absRateDiff(candidate, r), absRateDiff(best, r)
+		if distance < bestDistance || (distance == bestDistance && candidate > best) {
+			best = candidate
+		}
+	}
+	return best, true
+}
+
+// PreferredSampleRate picks the most preferred standard rate out of rates,
+// falling back to the first rate when none of them is standard
+func PreferredSampleRate(rates []int) (SampleRate, bool) {
+	if len(rates) == 0 {
+		return 0, false
+	}
+	for _, preferred := range StandardSampleRates {
+		if preferred.SupportedBy(rates) {
+			return preferred, true
+		}
+	}
+	return SampleRate(rates[0]), true
+}
+
+func absRateDiff(a, b SampleRate) SampleRate {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// Bounds for sample rates accepted from clients
+const (
+	MinSampleRate = 8000
+	MaxSampleRate = 384000
+)
+
+// ValidateSampleRateValue rejects sample rates that are not finite or fall
+// outside MinSampleRate-MaxSampleRate
+func ValidateSampleRateValue(rate float64) error {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("invalid sample rate: %v (must be a finite number)", rate)
+	}
+	if rate < MinSampleRate || rate > MaxSampleRate {
+		return fmt.Errorf("invalid sample rate: %.0f Hz (must be %d-%d Hz)", rate, MinSampleRate, MaxSampleRate)
+	}
+	return nil
+}
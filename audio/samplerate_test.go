@@ -0,0 +1,93 @@
+package audio
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// TestSampleRateIsStandard verifies standard and non-standard rates are told apart
+func TestSampleRateIsStandard(t *testing.T) {
+	for _, rate := range []SampleRate{44100, 48000, 96000, 192000} {
+		if !rate.IsStandard() {
+			t.Errorf("Expected %d to be standard", rate)
+		}
+	}
+	for _, rate := range []SampleRate{0, 22050, 88200, 176400} {
+		if rate.IsStandard() {
+			t.Errorf("Expected %d not to be standard", rate)
+		}
+	}
+}
+
+// TestSampleRateNyquist verifies the Nyquist frequency is half the rate
+func TestSampleRateNyquist(t *testing.T) {
+	if got := SampleRate(48000).Nyquist(); got != 24000 {
+		t.Errorf("Expected 24000, got %v", got)
+	}
+	if got := SampleRate(44100).Nyquist(); got != 22050 {
+		t.Errorf("Expected 22050, got %v", got)
+	}
+}
+
+// TestSampleRateNearestSupported verifies the closest rate is chosen, preferring higher on ties
+func TestSampleRateNearestSupported(t *testing.T) {
+	rates := []int{44100, 48000, 96000}
+
+	cases := map[SampleRate]SampleRate{
+		48000:  48000,
+		88200:  96000,
+		50000:  48000,
+		192000: 96000,
+		46050:  48000, // equidistant from 44100 and 48000
+	}
+	for requested, expected := range cases {
+		got, ok := requested.NearestSupported(rates)
+		if !ok || got != expected {
+			t.Errorf("NearestSupported(%d): expected %d, got %d (ok=%v)", requested, expected, got, ok)
+		}
+	}
+
+	if _, ok := SampleRate(48000).NearestSupported(nil); ok {
+		t.Error("Expected no nearest rate for an empty list")
+	}
+}
+
+// TestPreferredSampleRate verifies standard rates win in preference order
+func TestPreferredSampleRate(t *testing.T) {
+	if got, _ := PreferredSampleRate([]int{192000, 96000, 48000}); got != 48000 {
+		t.Errorf("Expected 48000, got %d", got)
+	}
+	if got, _ := PreferredSampleRate([]int{88200, 176400}); got != 88200 {
+		t.Errorf("Expected first rate when none is standard, got %d", got)
+	}
+	if _, ok := PreferredSampleRate(nil); ok {
+		t.Error("Expected no preferred rate for an empty list")
+	}
+}
+
+// TestValidateSampleRateValue verifies non-finite and out-of-range rates are rejected
+func TestValidateSampleRateValue(t *testing.T) {
+	for _, rate := range []float64{44100, 48000, 8000, 384000} {
+		if err := ValidateSampleRateValue(rate); err != nil {
+			t.Errorf("Expected %v to be valid, got: %v", rate, err)
+		}
+	}
+	for _, rate := range []float64{0, -48000, math.NaN(), math.Inf(1), 7999, 1e12} {
+		if err := ValidateSampleRateValue(rate); err == nil {
+			t.Errorf("Expected %v to be rejected", rate)
+		}
+	}
+}
+
+// TestExpandSampleRateRanges verifies ranges expand to nominal rates and discrete entries are kept
+func TestExpandSampleRateRanges(t *testing.T) {
+	got := ExpandSampleRateRanges([][2]int{{8000, 48000}, {12345, 12345}, {44100, 44100}})
+	want := []int{8000, 11025, 12345, 16000, 22050, 32000, 44100, 48000}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := ExpandSampleRateRanges(nil); len(got) != 0 {
+		t.Errorf("Expected no rates without ranges, got %v", got)
+	}
+}
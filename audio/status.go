@@ -0,0 +1,121 @@
+package audio
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// DSPLoadWarningThreshold is the DSP load above which dropouts become likely
+const DSPLoadWarningThreshold = 0.85
+
+// AudioHostStatus is the parsed form of audio-host's STATUS line
+type AudioHostStatus struct {
+	Raw        string  `json:"raw"`
+	Running    bool    `json:"running"`
+	SampleRate float64 `json:"sampleRate"`
+	BufferSize int     `json:"bufferSize"`
+	TestTone   bool    `json:"testTone"`
+	ToneFreq   float64 `json:"toneFreq"`
+	DSPLoad    float64 `json:"dspLoad"` // Fraction of each buffer's time budget spent rendering
+	XRunCount  int     `json:"xrunCount"`
+	XRunDelta  int     `json:"xrunDelta"` // New xruns since the previous PollStatus call on this process
+}
+
+// parseStatusResponse parses a line such as
+// "STATUS: running=true sampleRate=48000 bufferSize=256 testTone=false toneFreq=440.0 dspLoad=0.12 xruns=0".
+// Unknown keys are ignored so newer audio-host builds can add fields.
+func parseStatusResponse(line string) (AudioHostStatus, error) {
+	fields, ok := strings.CutPrefix(strings.TrimSpace(line), "STATUS:")
+	if !ok {
+		return AudioHostStatus{}, fmt.Errorf("unexpected status response: %q", line)
+	}
+
+	status := AudioHostStatus{Raw: line}
+	for _, field := range strings.Fields(fields) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			continue
+		}
+
+		var err error
+		switch key {
+		case "running":
+			status.Running, err = strconv.ParseBool(value)
+		case "sampleRate":
+			status.SampleRate, err = strconv.ParseFloat(value, 64)
+		case "bufferSize":
+			status.BufferSize, err = strconv.Atoi(value)
+		case "testTone":
+			status.TestTone, err = strconv.ParseBool(value)
+		case "toneFreq":
+			status.ToneFreq, err = strconv.ParseFloat(value, 64)
+		case "dspLoad":
+			status.DSPLoad, err = strconv.ParseFloat(value, 64)
+		case "xruns":
+			status.XRunCount, err = strconv.Atoi(value)
+		}
+		if err != nil {
+			return AudioHostStatus{}, fmt.Errorf("invalid %s value %q: %v", key, value, err)
+		}
+	}
+	return status, nil
+}
+
+// Warnings returns human-readable warnings for conditions likely to cause dropouts
+func (s AudioHostStatus) Warnings() []string {
+	var warnings []string
+	if s.DSPLoad > DSPLoadWarningThreshold {
+		warnings = append(warnings, fmt.Sprintf("DSP load at %.0f%% - dropouts likely, try a larger buffer size", s.DSPLoad*100))
+	}
+	if s.TestTone && s.SampleRate > 0 && s.ToneFreq >= SampleRate(s.SampleRate).Nyquist() {
+		warnings = append(warnings, fmt.Sprintf("test tone at %.0f Hz is above the %.0f Hz Nyquist limit and will alias",
+			s.ToneFreq, SampleRate(s.SampleRate).Nyquist()))
+	}
+	if s.XRunDelta > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d dropouts since last status check", s.XRunDelta))
+	}
+	return warnings
+}
+
+// Status queries audio-host for its current status. XRunDelta is left at
+// zero; see PollStatus.
+func (p *AudioHostProcess) Status() (AudioHostStatus, error) {
+	return p.queryStatus(false)
+}
+
+// PollStatus is Status plus XRunDelta, the dropouts since the previous
+// PollStatus call. Only the status endpoint polls, so other readers such as
+// the bootstrap and debug pages don't consume the delta the UI reports.
+func (p *AudioHostProcess) PollStatus() (AudioHostStatus, error) {
+	return p.queryStatus(true)
+}
+
+func (p *AudioHostProcess) queryStatus(poll bool) (AudioHostStatus, error) {
+	response, err := p.SendCommand("status")
+	if err != nil {
+		return AudioHostStatus{}, err
+	}
+
+	status, err := parseStatusResponse(response)
+	if err != nil {
+		return AudioHostStatus{}, err
+	}
+
+	if poll {
+		p.statusMu.Lock()
+		status.XRunDelta = status.XRunCount - p.lastXRunCount
+		if status.XRunDelta < 0 {
+			// Counter went backwards, so audio-host restarted counting
+			status.XRunDelta = status.XRunCount
+		}
+		p.lastXRunCount = status.XRunCount
+		p.statusMu.Unlock()
+	}
+
+	for _, warning := range status.Warnings() {
+		slog.Warn("audio-host status warning", "pid", p.GetPID(), "warning", warning)
+	}
+	return status, nil
+}
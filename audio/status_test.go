@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseStatusResponse verifies all STATUS fields are parsed
+func TestParseStatusResponse(t *testing.T) {
+	line := "STATUS: running=true sampleRate=48000 bufferSize=256 testTone=false toneFreq=440.0 dspLoad=0.12"
+	status, err := parseStatusResponse(line)
+	if err != nil {
+		t.Fatalf("parseStatusResponse failed: %v", err)
+	}
+
+	expected := AudioHostStatus{
+		Raw: line, Running: true, SampleRate: 48000, BufferSize: 256, TestTone: false, ToneFreq: 440, DSPLoad: 0.12,
+	}
+	if status != expected {
+		t.Errorf("Expected %+v, got %+v", expected, status)
+	}
+	if warnings := status.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings at 12%% load, got %v", warnings)
+	}
+}
+
+// TestParseStatusResponseHighDSPLoad verifies high DSP load is parsed and warned about
+func TestParseStatusResponseHighDSPLoad(t *testing.T) {
+	status, err := parseStatusResponse("STATUS: running=true sampleRate=48000 bufferSize=64 testTone=false toneFreq=440.0 dspLoad=0.9")
+	if err != nil {
+		t.Fatalf("parseStatusResponse failed: %v", err)
+	}
+	if status.DSPLoad != 0.9 {
+		t.Errorf("Expected dspLoad 0.9, got %v", status.DSPLoad)
+	}
+	if warnings := status.Warnings(); len(warnings) != 1 {
+		t.Errorf("Expected one DSP load warning, got %v", warnings)
+	}
+}
+
+// TestParseStatusResponseInvalid verifies malformed status lines are rejected
+func TestParseStatusResponseInvalid(t *testing.T) {
+	for _, line := range []string{
+		"OK: started",
+		"STATUS: running=maybe",
+		"STATUS: running=true dspLoad=high",
+	} {
+		if _, err := parseStatusResponse(line); err == nil {
+			t.Errorf("Expected %q to be rejected", line)
+		}
+	}
+}
+
+// TestParseStatusResponseXRuns verifies the xrun counter is parsed and warned about when it grows
+func TestParseStatusResponseXRuns(t *testing.T) {
+	status, err := parseStatusResponse("STATUS: running=true sampleRate=48000 bufferSize=64 testTone=false toneFreq=440.0 dspLoad=0.40 xruns=7")
+	if err != nil {
+		t.Fatalf("parseStatusResponse failed: %v", err)
+	}
+	if status.XRunCount != 7 {
+		t.Errorf("Expected 7 xruns, got %d", status.XRunCount)
+	}
+
+	status.XRunDelta = 3
+	if warnings := status.Warnings(); len(warnings) != 1 {
+		t.Errorf("Expected one dropout warning, got %v", warnings)
+	}
+}
+
+// TestStatusWarnsAboveNyquist verifies a test tone above half the sample rate is flagged
+func TestStatusWarnsAboveNyquist(t *testing.T) {
+	status, err := parseStatusResponse("STATUS: running=true sampleRate=44100 bufferSize=256 testTone=true toneFreq=30000.0 dspLoad=0.10 xruns=0")
+	if err != nil {
+		t.Fatalf("parseStatusResponse failed: %v", err)
+	}
+	warnings := status.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "22050 Hz Nyquist") {
+		t.Errorf("Expected one Nyquist warning, got %v", warnings)
+	}
+
+	status.TestTone = false
+	if warnings := status.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warning with the tone off, got %v", warnings)
+	}
+}
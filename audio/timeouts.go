@@ -0,0 +1,35 @@
+package audio
+
+import "time"
+
+// AudioTimeouts bounds how long the server waits on audio-host
+type AudioTimeouts struct {
+	Ready   time.Duration // For the ready sentinel after launch
+	Command time.Duration // For the response to one command
+	Stop    time.Duration // For the process to exit after quit before it is killed
+}
+
+// DefaultAudioTimeouts suit a typical machine; slow systems may need a longer
+// Ready timeout for device initialization
+var DefaultAudioTimeouts = AudioTimeouts{
+	Ready:   5 * time.Second,
+	Command: 5 * time.Second,
+	Stop:    3 * time.Second,
+}
+
+// Timeouts is the configured set, read each time audio-host is waited on
+var Timeouts = DefaultAudioTimeouts
+
+// effective returns t with unset or negative fields replaced by the defaults
+func (t AudioTimeouts) effective() AudioTimeouts {
+	if t.Ready <= 0 {
+		t.Ready = DefaultAudioTimeouts.Ready
+	}
+	if t.Command <= 0 {
+		t.Command = DefaultAudioTimeouts.Command
+	}
+	if t.Stop <= 0 {
+		t.Stop = DefaultAudioTimeouts.Stop
+	}
+	return t
+}
@@ -0,0 +1,18 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAudioTimeoutsEffective verifies unset timeouts fall back to the defaults
+func TestAudioTimeoutsEffective(t *testing.T) {
+	got := AudioTimeouts{Ready: 20 * time.Second, Stop: -1}.effective()
+
+	if got.Ready != 20*time.Second {
+		t.Errorf("Expected the configured READY timeout, got %v", got.Ready)
+	}
+	if got.Command != DefaultAudioTimeouts.Command || got.Stop != DefaultAudioTimeouts.Stop {
+		t.Errorf("Expected defaults for unset timeouts, got %+v", got)
+	}
+}
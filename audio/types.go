@@ -5,18 +5,38 @@ import (
 	"io"
 	"os/exec"
 	"sync"
+	"time"
 )
 
 // Device structures based on standalone/devices output
 type AudioDevice struct {
-	DeviceID             int    `json:"deviceId"`
-	UID                  string `json:"uid"`
-	SupportedSampleRates []int  `json:"supportedSampleRates"`
-	ChannelCount         int    `json:"channelCount"`
-	IsDefault            bool   `json:"isDefault"`
-	IsOnline             bool   `json:"isOnline"`
-	Name                 string `json:"name"`
-	SupportedBitDepths   []int  `json:"supportedBitDepths"`
+	DeviceID             int           `json:"deviceId"`
+	UID                  string        `json:"uid"`
+	SupportedSampleRates []int         `json:"supportedSampleRates"`
+	ChannelCount         int           `json:"channelCount"`
+	IsDefault            bool          `json:"isDefault"`
+	IsOnline             bool          `json:"isOnline"`
+	Name                 string        `json:"name"`
+	SupportedBitDepths   []int         `json:"supportedBitDepths"`
+	HogModePID           int           `json:"hogModePid"`
+	SampleRateRanges     [][2]int      `json:"sampleRateRanges,omitempty"`
+	StreamFormat         *StreamFormat `json:"streamFormat,omitempty"`
+	TransportType        string        `json:"transportType,omitempty"` // "builtin", "usb", "aggregate", ...
+}
+
+// TransportAggregate is the transport type CoreAudio reports for aggregate devices
+const TransportAggregate = "aggregate"
+
+// IsAggregate reports whether the device is a CoreAudio aggregate device
+func (d AudioDevice) IsAggregate() bool {
+	return d.TransportType == TransportAggregate
+}
+
+// StreamFormat describes the sample format a device's stream runs in
+type StreamFormat struct {
+	SampleFormat   string `json:"sampleFormat"` // e.g. "float32", "int16", "int24"
+	BitsPerChannel int    `json:"bitsPerChannel"`
+	Interleaved    bool   `json:"interleaved"`
 }
 
 // Implement debug.Device interface for AudioDevice
@@ -38,18 +58,32 @@ type DefaultDevices struct {
 	DefaultOutput int `json:"defaultOutput"`
 }
 
+// DeviceOrdering controls how audio device lists are ordered after loading
+type DeviceOrdering int
+
+const (
+	// OrderDefaultFirst puts the default device first, then online devices
+	// alphabetically by name, then offline devices
+	OrderDefaultFirst DeviceOrdering = iota
+	// OrderNative keeps the order reported by CoreAudio
+	OrderNative
+)
+
 type DevicesData struct {
-	TotalMIDIInputDevices   int            `json:"totalMIDIInputDevices"`
-	MIDIInput               []MIDIDevice   `json:"midiInput"`
-	Defaults                DefaultDevices `json:"defaults"`
-	TotalAudioInputDevices  int            `json:"totalAudioInputDevices"`
-	AudioInput              []AudioDevice  `json:"audioInput"`
-	AudioOutput             []AudioDevice  `json:"audioOutput"`
-	TotalMIDIOutputDevices  int            `json:"totalMIDIOutputDevices"`
-	Timestamp               string         `json:"timestamp"`
-	MIDIOutput              []MIDIDevice   `json:"midiOutput"`
-	TotalAudioOutputDevices int            `json:"totalAudioOutputDevices"`
-	DefaultSampleRate       float64        `json:"defaultSampleRate"`
+	TotalMIDIInputDevices   int               `json:"totalMIDIInputDevices"`
+	MIDIInput               []MIDIDevice      `json:"midiInput"`
+	Defaults                DefaultDevices    `json:"defaults"`
+	TotalAudioInputDevices  int               `json:"totalAudioInputDevices"`
+	AudioInput              []AudioDevice     `json:"audioInput"`
+	AudioOutput             []AudioDevice     `json:"audioOutput"`
+	TotalMIDIOutputDevices  int               `json:"totalMIDIOutputDevices"`
+	Timestamp               string            `json:"timestamp"`
+	MIDIOutput              []MIDIDevice      `json:"midiOutput"`
+	TotalAudioOutputDevices int               `json:"totalAudioOutputDevices"`
+	DefaultSampleRate       float64           `json:"defaultSampleRate"`
+	Errors                  map[string]string `json:"errors,omitempty"`
+	LoadedAt                time.Time         `json:"loadedAt"`
+	Sequence                uint64            `json:"sequence"`
 }
 
 // Plugin structures based on standalone/inspector output
@@ -75,6 +109,7 @@ type Plugin struct {
 	Name           string            `json:"name"`
 	Type           string            `json:"type"`
 	Subtype        string            `json:"subtype"`
+	Category       string            `json:"category"` // Derived from Type, see PluginCategory
 }
 
 // Server data - holds the results of both tools
@@ -96,6 +131,8 @@ type AudioConfig struct {
 // Audio start request
 type StartAudioRequest struct {
 	Config AudioConfig `json:"config"`
+	// SafeMode retries once with SafeModeConfig if Config fails to start
+	SafeMode bool `json:"safeMode,omitempty"`
 }
 
 // Structured response from audio-host commands
@@ -119,9 +156,12 @@ const (
 
 // Audio start response
 type StartAudioResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	PID     int    `json:"pid,omitempty"`
+	Success        bool         `json:"success"`
+	Message        string       `json:"message"`
+	PID            int          `json:"pid,omitempty"`
+	FellBack       bool         `json:"fellBack,omitempty"`
+	FallbackReason string       `json:"fallbackReason,omitempty"`
+	Config         *AudioConfig `json:"config,omitempty"`
 }
 
 // Audio command request
@@ -162,13 +202,30 @@ type DeviceSwitchRequest struct {
 
 // Device switch response with boolean ready state
 type DeviceSwitchResponse struct {
-	IsAudioReady           bool        `json:"isAudioReady"`
-	ErrorMessage           string      `json:"errorMessage,omitempty"`
-	RequiredAction         string      `json:"requiredAction,omitempty"`
-	NewConfig              AudioConfig `json:"newConfig"`
-	PreviousProcessRunning bool        `json:"previousProcessRunning"`
-	ProcessRestarted       bool        `json:"processRestarted"`
-	PID                    int         `json:"pid,omitempty"`
+	IsAudioReady           bool                   `json:"isAudioReady"`
+	ErrorMessage           string                 `json:"errorMessage,omitempty"`
+	RequiredAction         string                 `json:"requiredAction,omitempty"`
+	NewConfig              AudioConfig            `json:"newConfig"`
+	PreviousProcessRunning bool                   `json:"previousProcessRunning"`
+	ProcessRestarted       bool                   `json:"processRestarted"`
+	PID                    int                    `json:"pid,omitempty"`
+	ChangeType             string                 `json:"changeType,omitempty"`
+	Details                *ReconfigurationResult `json:"details,omitempty"`
+}
+
+// MIDI message types accepted by MIDISendRequest
+const (
+	MIDIControlChange = "cc"
+	MIDIProgramChange = "program-change"
+)
+
+// MIDI send request for mirroring values out to a MIDI output device
+type MIDISendRequest struct {
+	DeviceUID string `json:"deviceUID"`
+	Type      string `json:"type,omitempty"` // MIDIControlChange (default) or MIDIProgramChange
+	Channel   int    `json:"channel"`        // 1-16
+	CC        int    `json:"cc,omitempty"`   // Controller number, ignored for program changes
+	Value     int    `json:"value"`          // CC value or program number
 }
 
 // AudioHost process management
@@ -182,6 +239,24 @@ type AudioHostProcess struct {
 	mu      sync.RWMutex
 	ctx     context.Context
 	cancel  context.CancelFunc
+	exited  chan struct{} // Closed once cmd.Wait returns
+
+	cmdMu     sync.Mutex  // Serializes command round-trips
+	responses chan string // Lines read from stdout; closed once it ends
+	stale     int         // Responses still owed to commands that timed out; guarded by cmdMu
+
+	requested AudioConfig // Configuration the process was started with
+	readyInfo *ReadyInfo  // Configuration reported with READY, if any
+	commands  []string    // Commands audio-host reported at startup; nil if unknown
+
+	commandLog *CommandLog // Recent command/response pairs; nil unless CommandLogSize > 0
+
+	statusMu      sync.Mutex // Guards lastXRunCount
+	lastXRunCount int        // xrun count seen by the previous PollStatus call
+
+	levelMu     sync.Mutex // Serializes input level queries
+	lastLevel   InputLevel // Most recent input level, reused within InputLevelMinInterval
+	lastLevelAt time.Time
 }
 
 // Configuration management types
@@ -198,6 +273,9 @@ const (
 type ConfigChange struct {
 	NewConfig    AudioConfig
 	ChangeReason string
+	// RequireRunning makes the change leave audio-host running with NewConfig,
+	// starting it if it isn't running instead of only recording the config
+	RequireRunning bool
 }
 
 // ReconfigurationResult contains the outcome of a reconfiguration attempt
@@ -213,8 +291,19 @@ type ReconfigurationResult struct {
 	NewPID           int
 }
 
+// ChangeCapabilities lists which AudioConfig fields can change without
+// interrupting audio in the current running state. Names match the JSON keys.
+type ChangeCapabilities struct {
+	Running         bool     `json:"running"`
+	Dynamic         []string `json:"dynamic"`
+	RequiresRestart []string `json:"requiresRestart"`
+}
+
 // AudioEngineReconfiguration handles changes that require rebuilding the audio chain
 type AudioEngineReconfiguration struct {
+	// mu guards currentConfig and isRunning so status readers can run
+	// alongside a change. Whole transitions are serialized by Lifecycle.
+	mu            sync.Mutex
 	currentConfig *AudioConfig
 	isRunning     bool
 }
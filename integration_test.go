@@ -0,0 +1,1107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shaban/rackless/audio"
+)
+
+// =============================================================================
+// FAKE AUDIO-HOST HARNESS
+// =============================================================================
+
+var (
+	fakeHostOnce sync.Once
+	fakeHostDir  string
+	fakeHostErr  error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if fakeHostDir != "" {
+		os.RemoveAll(fakeHostDir)
+	}
+	os.Exit(code)
+}
+
+// buildFakeAudioHost compiles testdata/fake-audio-host once per test run into a
+// data directory laid out like a real install, and returns that directory
+func buildFakeAudioHost(t *testing.T) string {
+	t.Helper()
+	fakeHostOnce.Do(func() {
+		goTool, err := exec.LookPath("go")
+		if err != nil {
+			fakeHostErr = err
+			return
+		}
+
+		fakeHostDir, fakeHostErr = os.MkdirTemp("", "rackless-fake-host")
+		if fakeHostErr != nil {
+			return
+		}
+
+		output := filepath.Join(fakeHostDir, audio.AudioHostToolPath)
+		cmd := exec.Command(goTool, "build", "-o", output, "./testdata/fake-audio-host")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			fakeHostErr = fmt.Errorf("%v: %s", err, out)
+		}
+	})
+
+	if fakeHostErr != nil {
+		t.Skipf("Fake audio-host unavailable: %v", fakeHostErr)
+	}
+	return fakeHostDir
+}
+
+// useFakeAudioHost points the process launcher at the fake audio-host and
+// loads the device fixture so start requests pass validation. Each test gets
+// its own data directory so saved state doesn't leak between tests.
+func useFakeAudioHost(t *testing.T) {
+	t.Helper()
+	built := filepath.Join(buildFakeAudioHost(t), audio.AudioHostToolPath)
+
+	dir := t.TempDir()
+	hostPath := filepath.Join(dir, audio.AudioHostToolPath)
+	if err := os.MkdirAll(filepath.Dir(hostPath), 0755); err != nil {
+		t.Fatalf("Failed to create audio-host directory: %v", err)
+	}
+	if err := os.Symlink(built, hostPath); err != nil {
+		t.Fatalf("Failed to link fake audio-host: %v", err)
+	}
+
+	originalDir := audio.DataDir
+	originalReconfig := audio.Reconfig
+	originalBreaker := audio.StartFailures
+	audio.DataDir = dir
+	audio.Reconfig = audio.NewAudioEngineReconfiguration()
+	audio.StartFailures = audio.NewStartBreaker(audio.DefaultBreakerThreshold, audio.DefaultBreakerWindow, audio.DefaultBreakerCooldown)
+	useTestDevices(t)
+
+	t.Cleanup(func() {
+		stopAudioHost()
+		audio.DataDir = originalDir
+		audio.Reconfig = originalReconfig
+		audio.StartFailures = originalBreaker
+	})
+}
+
+// postJSON sends a JSON request through the router and returns the recorder
+func postJSON(t *testing.T, router http.Handler, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	req := httptest.NewRequest("POST", path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// sendCommand runs a command through the API and returns the audio-host output
+func sendCommand(t *testing.T, router http.Handler, command string) string {
+	t.Helper()
+	w := postJSON(t, router, "/api/audio/command", audio.AudioCommandRequest{Command: command})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Command %q failed with status %d: %s", command, w.Code, w.Body.String())
+	}
+
+	var response audio.AudioCommandResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode command response: %v", err)
+	}
+	return response.Output
+}
+
+// =============================================================================
+// INTEGRATION TESTS
+// =============================================================================
+
+// TestFakeAudioHostLifecycle runs a full start → command → stop cycle without audio hardware
+func TestFakeAudioHostLifecycle(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+
+	startReq := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000, BufferSize: 128}}
+	w := postJSON(t, router, "/api/audio/start", startReq)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var started audio.StartAudioResponse
+	if err := json.NewDecoder(w.Body).Decode(&started); err != nil {
+		t.Fatalf("Failed to decode start response: %v", err)
+	}
+	if !started.Success || started.PID == 0 {
+		t.Fatalf("Expected successful start with a PID, got %+v", started)
+	}
+
+	if output := sendCommand(t, router, "ping"); output != "OK: pong" {
+		t.Errorf("Unexpected ping response: %q", output)
+	}
+
+	status := sendCommand(t, router, "status")
+	if !contains(status, "sampleRate=48000") || !contains(status, "bufferSize=128") {
+		t.Errorf("Status does not reflect start config: %q", status)
+	}
+
+	if output := sendCommand(t, router, "tone freq 880"); output != "OK: frequency set to 880.0" {
+		t.Errorf("Unexpected tone response: %q", output)
+	}
+	if output := sendCommand(t, router, "load-plugin aufx:dely:appl"); output != "OK: plugin loaded" {
+		t.Errorf("Unexpected load-plugin response: %q", output)
+	}
+
+	w = postJSON(t, router, "/api/audio/stop", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Stop failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+	if process != nil {
+		t.Error("Expected no audio-host process after stop")
+	}
+	t.Logf("✅ Start → command → stop cycle completed against fake audio-host (PID %d)", started.PID)
+}
+
+// TestResumeAudioWithSavedConfig verifies audio restarts from the saved configuration
+func TestResumeAudioWithSavedConfig(t *testing.T) {
+	useFakeAudioHost(t)
+
+	if err := audio.SaveLastConfig(audio.AudioConfig{SampleRate: 48000, BufferSize: 256, AudioInputDeviceID: 145}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	resumed, err := resumeAudio()
+	if err != nil || !resumed {
+		t.Fatalf("Expected audio to resume, got resumed=%v err=%v", resumed, err)
+	}
+
+	status := sendCommand(t, setupRoutes(), "status")
+	if !contains(status, "sampleRate=48000") {
+		t.Errorf("Resumed process does not use saved config: %q", status)
+	}
+}
+
+// TestResumeAudioSkippedWhenDeviceGone verifies auto-start is skipped if the saved input device disappeared
+func TestResumeAudioSkippedWhenDeviceGone(t *testing.T) {
+	useFakeAudioHost(t)
+
+	if err := audio.SaveLastConfig(audio.AudioConfig{SampleRate: 48000, AudioInputDeviceID: 105}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	audio.Data.Devices.AudioInput = audio.Data.Devices.AudioInput[:1]
+
+	resumed, err := resumeAudio()
+	if err == nil || resumed {
+		t.Fatalf("Expected resume to be skipped, got resumed=%v err=%v", resumed, err)
+	}
+	if audio.Process != nil {
+		t.Error("Expected no audio-host process when resume is skipped")
+	}
+	t.Logf("✅ Resume skipped: %v", err)
+}
+
+// stubStartSavedAudio replaces the auto-start path and reports whether it was invoked
+func stubStartSavedAudio(t *testing.T) *bool {
+	t.Helper()
+	invoked := false
+	original := startSavedAudio
+	startSavedAudio = func() (bool, error) {
+		invoked = true
+		return true, nil
+	}
+	t.Cleanup(func() { startSavedAudio = original })
+	return &invoked
+}
+
+// TestAutoStartEnabled verifies the start path runs when auto-start is on
+func TestAutoStartEnabled(t *testing.T) {
+	invoked := stubStartSavedAudio(t)
+	autoStartAudio(true)
+	if !*invoked {
+		t.Error("Expected saved audio configuration to be started")
+	}
+}
+
+// TestAutoStartDisabled verifies the start path is not touched when auto-start is off
+func TestAutoStartDisabled(t *testing.T) {
+	invoked := stubStartSavedAudio(t)
+	autoStartAudio(false)
+	if *invoked {
+		t.Error("Expected auto-start to be skipped")
+	}
+}
+
+// startFakeAudio starts the fake audio-host through the API and fails the test if it doesn't start
+func startFakeAudio(t *testing.T, router http.Handler) {
+	t.Helper()
+	w := postJSON(t, router, "/api/audio/start", audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestInputLevelFromFakeHost verifies the input level snapshot is read from audio-host
+func TestInputLevelFromFakeHost(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	req := httptest.NewRequest("GET", "/api/audio/input-level", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Input level failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var level audio.InputLevel
+	if err := json.NewDecoder(w.Body).Decode(&level); err != nil {
+		t.Fatalf("Failed to decode input level: %v", err)
+	}
+	if level.PeakDB != -12.0 || level.RMSDB != -18.5 {
+		t.Errorf("Unexpected input level: %+v", level)
+	}
+
+	// A second read within the throttle interval reuses the same reading
+	again, err := audio.Process.InputLevel()
+	if err != nil {
+		t.Fatalf("Second input level read failed: %v", err)
+	}
+	if !again.Timestamp.Equal(level.Timestamp) {
+		t.Errorf("Expected throttled read to reuse timestamp %v, got %v", level.Timestamp, again.Timestamp)
+	}
+}
+
+// TestAudioStatusDSPLoadWarning verifies DSP load is reported and warned about above the threshold
+func TestAudioStatusDSPLoadWarning(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+	sendCommand(t, router, "set-dsp-load 0.9")
+
+	req := httptest.NewRequest("GET", "/api/audio/status", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var status struct {
+		EngineRunning bool     `json:"engineRunning"`
+		DSPLoad       float64  `json:"dspLoad"`
+		Warnings      []string `json:"warnings"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if !status.EngineRunning || status.DSPLoad != 0.9 {
+		t.Errorf("Expected running engine at 0.9 load, got %+v", status)
+	}
+	if len(status.Warnings) != 1 {
+		t.Errorf("Expected one DSP load warning, got %v", status.Warnings)
+	}
+}
+
+// TestAudioStatusXRunDelta verifies dropouts are counted as a delta between status reads
+func TestAudioStatusXRunDelta(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	sendCommand(t, router, "add-xruns 2")
+	first, err := audio.Process.PollStatus()
+	if err != nil {
+		t.Fatalf("First status read failed: %v", err)
+	}
+	if first.XRunCount != 2 || first.XRunDelta != 2 {
+		t.Errorf("Expected 2 xruns with delta 2, got count=%d delta=%d", first.XRunCount, first.XRunDelta)
+	}
+
+	sendCommand(t, router, "add-xruns 3")
+	second, err := audio.Process.PollStatus()
+	if err != nil {
+		t.Fatalf("Second status read failed: %v", err)
+	}
+	if second.XRunCount != 5 || second.XRunDelta != 3 {
+		t.Errorf("Expected 5 xruns with delta 3, got count=%d delta=%d", second.XRunCount, second.XRunDelta)
+	}
+	if len(second.Warnings()) != 1 {
+		t.Errorf("Expected a dropout warning, got %v", second.Warnings())
+	}
+
+	third, _ := audio.Process.PollStatus()
+	if third.XRunDelta != 0 || len(third.Warnings()) != 0 {
+		t.Errorf("Expected no new dropouts, got delta=%d warnings=%v", third.XRunDelta, third.Warnings())
+	}
+}
+
+// TestAudioStatusEndpointXRunDelta verifies /api/audio/status reports the
+// dropouts since its previous read, unaffected by bootstrap reads in between
+func TestAudioStatusEndpointXRunDelta(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	getStatus := func() map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/audio/status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var status map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+		return status
+	}
+
+	sendCommand(t, router, "add-xruns 2")
+	if status := getStatus(); status["xrunCount"] != 2.0 || status["xrunDelta"] != 2.0 {
+		t.Errorf("Expected 2 xruns with delta 2, got count=%v delta=%v", status["xrunCount"], status["xrunDelta"])
+	}
+
+	sendCommand(t, router, "add-xruns 3")
+	bootstrap := httptest.NewRecorder()
+	router.ServeHTTP(bootstrap, httptest.NewRequest("GET", "/api/bootstrap", nil))
+	if bootstrap.Code != http.StatusOK {
+		t.Fatalf("Bootstrap failed with status %d", bootstrap.Code)
+	}
+
+	if status := getStatus(); status["xrunCount"] != 5.0 || status["xrunDelta"] != 3.0 {
+		t.Errorf("Expected 5 xruns with delta 3, got count=%v delta=%v", status["xrunCount"], status["xrunDelta"])
+	}
+}
+
+// getChangeCapabilities fetches /api/audio/change-capabilities
+func getChangeCapabilities(t *testing.T, router http.Handler) audio.ChangeCapabilities {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/audio/change-capabilities", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Change capabilities failed with status %d", w.Code)
+	}
+
+	var capabilities audio.ChangeCapabilities
+	if err := json.NewDecoder(w.Body).Decode(&capabilities); err != nil {
+		t.Fatalf("Failed to decode change capabilities: %v", err)
+	}
+	return capabilities
+}
+
+// TestChangeCapabilitiesRunningVsStopped verifies restart-only parameters appear once audio is running
+func TestChangeCapabilitiesRunningVsStopped(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+
+	stopped := getChangeCapabilities(t, router)
+	if stopped.Running || len(stopped.RequiresRestart) != 0 {
+		t.Errorf("Expected nothing to require a restart while stopped, got %+v", stopped)
+	}
+
+	startFakeAudio(t, router)
+
+	running := getChangeCapabilities(t, router)
+	if !running.Running {
+		t.Error("Expected running engine")
+	}
+	if !slices.Contains(running.RequiresRestart, "sampleRate") || !slices.Contains(running.Dynamic, "enableTestTone") {
+		t.Errorf("Expected sampleRate to need a restart and enableTestTone to be dynamic, got %+v", running)
+	}
+}
+
+// TestBootstrapSections verifies the bootstrap response carries consistent devices, plugins, config and status
+func TestBootstrapSections(t *testing.T) {
+	useFakeAudioHost(t)
+	originalPlugins := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(3, 4)
+	t.Cleanup(func() { audio.Data.Plugins = originalPlugins })
+
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	req := httptest.NewRequest("GET", "/api/bootstrap", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Bootstrap failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Failed to decode bootstrap: %v", err)
+	}
+	for _, section := range []string{"devices", "plugins", "config", "status"} {
+		if _, ok := raw[section]; !ok {
+			t.Errorf("Expected %q section in bootstrap response", section)
+		}
+	}
+
+	var response BootstrapResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode bootstrap: %v", err)
+	}
+	if len(response.Devices.AudioOutput) != len(audio.Data.Devices.AudioOutput) {
+		t.Errorf("Expected %d output devices, got %d", len(audio.Data.Devices.AudioOutput), len(response.Devices.AudioOutput))
+	}
+	if len(response.Plugins) != 3 || response.Plugins[0].ParameterCount != 4 {
+		t.Errorf("Expected 3 plugin summaries with 4 parameters, got %+v", response.Plugins)
+	}
+	if response.Config.Current == nil || response.Config.Saved == nil ||
+		response.Config.Current.SampleRate != response.Config.Saved.Config.SampleRate {
+		t.Errorf("Expected matching current and saved config, got %+v", response.Config)
+	}
+	if running, _ := response.Status["processRunning"].(bool); !running {
+		t.Errorf("Expected running process in status, got %v", response.Status)
+	}
+}
+
+// TestUnsetBufferSizeUsesConfiguredDefault verifies start, test-devices and switch-devices share one default
+func TestUnsetBufferSizeUsesConfiguredDefault(t *testing.T) {
+	useFakeAudioHost(t)
+	originalBuffer := audio.DefaultBufferSize
+	audio.DefaultBufferSize = 512
+	t.Cleanup(func() { audio.DefaultBufferSize = originalBuffer })
+
+	router := setupRoutes()
+
+	w := postJSON(t, router, "/api/audio/test-devices", audio.DeviceTestRequest{InputDeviceID: 145, SampleRate: 48000})
+	var tested audio.DeviceTestResponse
+	if err := json.NewDecoder(w.Body).Decode(&tested); err != nil {
+		t.Fatalf("Failed to decode test-devices response: %v", err)
+	}
+	if tested.TestedConfig.BufferSize != 512 {
+		t.Errorf("test-devices: expected buffer size 512, got %d", tested.TestedConfig.BufferSize)
+	}
+
+	startFakeAudio(t, router)
+	if status := sendCommand(t, router, "status"); !contains(status, "bufferSize=512") {
+		t.Errorf("start: expected buffer size 512, got %q", status)
+	}
+
+	w = postJSON(t, router, "/api/audio/switch-devices", audio.DeviceSwitchRequest{InputDeviceID: 105, SampleRate: 48000})
+	var switched audio.DeviceSwitchResponse
+	if err := json.NewDecoder(w.Body).Decode(&switched); err != nil {
+		t.Fatalf("Failed to decode switch-devices response: %v", err)
+	}
+	if switched.NewConfig.BufferSize != 512 {
+		t.Errorf("switch-devices: expected buffer size 512, got %d", switched.NewConfig.BufferSize)
+	}
+}
+
+// TestStartDuringSwitchRejected verifies a start arriving mid-switch gets 409
+// with Retry-After instead of racing the switch's own launch
+func TestStartDuringSwitchRejected(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	// Slow down the switch's relaunch so the start lands inside it
+	t.Setenv("FAKE_AUDIO_HOST_READY_DELAY", "500ms")
+
+	switched := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		switched <- postJSON(t, router, "/api/audio/switch-devices",
+			audio.DeviceSwitchRequest{InputDeviceID: 105, SampleRate: 48000})
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for audio.Lifecycle.State() != audio.StateSwitching {
+		if time.Now().After(deadline) {
+			t.Fatal("Switch never entered the switching state")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	w := postJSON(t, router, "/api/audio/start", audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 during switch, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on lifecycle conflict")
+	}
+	var rejected map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&rejected); err != nil {
+		t.Fatalf("Failed to decode conflict response: %v", err)
+	}
+	if rejected["state"] != "switching" {
+		t.Errorf("Expected state switching, got %v", rejected["state"])
+	}
+
+	w = <-switched
+	var response audio.DeviceSwitchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode switch-devices response: %v", err)
+	}
+	if !response.IsAudioReady {
+		t.Fatalf("Expected switch to succeed, got %+v", response)
+	}
+	if state := audio.Lifecycle.State(); state != audio.StateRunning {
+		t.Errorf("Expected running after switch, got %s", state)
+	}
+}
+
+// TestCommandLogRecordsCommands verifies a sent command is logged with its response and timing
+func TestCommandLogRecordsCommands(t *testing.T) {
+	useFakeAudioHost(t)
+	originalSize := audio.CommandLogSize
+	audio.CommandLogSize = 16
+	t.Cleanup(func() { audio.CommandLogSize = originalSize })
+
+	router := setupRoutes()
+	startFakeAudio(t, router)
+	sendCommand(t, router, "ping")
+
+	req := httptest.NewRequest("GET", "/api/audio/command-log", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Enabled bool                    `json:"enabled"`
+		Entries []audio.CommandLogEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode command log: %v", err)
+	}
+	if !response.Enabled || len(response.Entries) == 0 {
+		t.Fatalf("Expected enabled log with entries, got %+v", response)
+	}
+
+	last := response.Entries[len(response.Entries)-1]
+	if last.Command != "ping" || last.Response != "OK: pong" {
+		t.Errorf("Expected ping/pong entry, got %+v", last)
+	}
+	if last.DurationMs <= 0 {
+		t.Errorf("Expected non-zero duration, got %v", last.DurationMs)
+	}
+}
+
+// currentProcess returns the running audio-host process or fails the test
+func currentProcess(t *testing.T) *audio.AudioHostProcess {
+	t.Helper()
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+	if process == nil {
+		t.Fatal("Expected a running audio-host process")
+	}
+	return process
+}
+
+// TestReadyPayloadRecorded verifies the negotiated sample rate from "READY {json}" is stored
+func TestReadyPayloadRecorded(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_READY_LINE", `READY {"sampleRate":48000}`)
+	router := setupRoutes()
+
+	w := postJSON(t, router, "/api/audio/start", audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 44100}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	info, ok := currentProcess(t).ReadyInfo()
+	if !ok || info.SampleRate != 48000 {
+		t.Errorf("Expected recorded sample rate 48000, got %+v (reported %v)", info, ok)
+	}
+}
+
+// TestCustomReadySentinel verifies startup succeeds with an overridden sentinel
+func TestCustomReadySentinel(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_READY_LINE", "HOST-UP")
+	originalSentinel := audio.ReadySentinel
+	audio.ReadySentinel = "HOST-UP"
+	t.Cleanup(func() { audio.ReadySentinel = originalSentinel })
+
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	if _, ok := currentProcess(t).ReadyInfo(); ok {
+		t.Error("Expected no ready payload from a bare sentinel")
+	}
+}
+
+// TestActualConfigDiffersFromRequested verifies status reports both configs when the host clamps the rate
+func TestActualConfigDiffersFromRequested(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_READY_LINE", `READY {"sampleRate":44100,"bufferSize":256}`)
+	router := setupRoutes()
+
+	w := postJSON(t, router, "/api/audio/start",
+		audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000, BufferSize: 256}})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/api/audio/status", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var status struct {
+		RequestedConfig audio.AudioConfig `json:"requestedConfig"`
+		ActualConfig    audio.AudioConfig `json:"actualConfig"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status.RequestedConfig.SampleRate != 48000 {
+		t.Errorf("Expected requested 48000, got %v", status.RequestedConfig.SampleRate)
+	}
+	if status.ActualConfig.SampleRate != 44100 {
+		t.Errorf("Expected actual 44100, got %v", status.ActualConfig.SampleRate)
+	}
+
+	current := audio.Reconfig.GetCurrentConfig()
+	if current == nil || current.SampleRate != 44100 {
+		t.Errorf("Expected reconfiguration to track the actual rate, got %+v", current)
+	}
+}
+
+// TestSafeModeStartFallsBack verifies a failing config falls back to safe-mode defaults when requested
+func TestSafeModeStartFallsBack(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	failing := audio.AudioConfig{SampleRate: 48000, AudioInputDeviceID: 999}
+
+	w := postJSON(t, router, "/api/audio/start", audio.StartAudioRequest{Config: failing})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without safe mode, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = postJSON(t, router, "/api/audio/start", audio.StartAudioRequest{Config: failing, SafeMode: true})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected safe-mode start to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response audio.StartAudioResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode start response: %v", err)
+	}
+	if !response.Success || !response.FellBack || response.FallbackReason == "" {
+		t.Fatalf("Expected a successful fallback with a reason, got %+v", response)
+	}
+	if response.Config == nil || response.Config.SampleRate != audio.SafeModeSampleRate ||
+		response.Config.AudioInputDeviceID != 145 {
+		t.Errorf("Expected safe-mode config on the default input, got %+v", response.Config)
+	}
+	if status := sendCommand(t, router, "status"); !contains(status, "sampleRate=44100") {
+		t.Errorf("Expected audio-host running at 44100, got %q", status)
+	}
+}
+
+// TestSwitchDevicesReportsChangeType verifies a rate change during switch is reported as a process restart
+func TestSwitchDevicesReportsChangeType(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+	oldPID := currentProcess(t).GetPID()
+
+	w := postJSON(t, router, "/api/audio/switch-devices",
+		audio.DeviceSwitchRequest{InputDeviceID: 145, SampleRate: 44100})
+	var response audio.DeviceSwitchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode switch-devices response: %v", err)
+	}
+
+	if !response.IsAudioReady || !response.ProcessRestarted {
+		t.Fatalf("Expected a successful restart, got %+v", response)
+	}
+	if response.ChangeType != "process-restart" {
+		t.Errorf("Expected change type process-restart, got %q", response.ChangeType)
+	}
+	if response.Details == nil || response.Details.OldPID != oldPID || response.Details.NewPID != response.PID {
+		t.Errorf("Expected details with old PID %d and new PID %d, got %+v", oldPID, response.PID, response.Details)
+	}
+	if response.Details.PreviousConfig == nil || response.Details.PreviousConfig.SampleRate != 48000 {
+		t.Errorf("Expected previous config at 48000, got %+v", response.Details.PreviousConfig)
+	}
+}
+
+// TestSwitchDevicesInvalidKeepsAudioRunning verifies a rejected switch leaves the running process alone
+func TestSwitchDevicesInvalidKeepsAudioRunning(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	startFakeAudio(t, router)
+	pid := currentProcess(t).GetPID()
+
+	w := postJSON(t, router, "/api/audio/switch-devices",
+		audio.DeviceSwitchRequest{InputDeviceID: 999, SampleRate: 48000})
+	var response audio.DeviceSwitchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode switch-devices response: %v", err)
+	}
+	if response.IsAudioReady || response.ErrorMessage == "" {
+		t.Fatalf("Expected the switch to fail, got %+v", response)
+	}
+	if process := currentProcess(t); process.GetPID() != pid || !process.IsRunning() {
+		t.Error("Expected the original audio-host to keep running")
+	}
+}
+
+// TestSwitchDevicesStartsStoppedAudio verifies switching while stopped starts audio-host
+func TestSwitchDevicesStartsStoppedAudio(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+
+	w := postJSON(t, router, "/api/audio/switch-devices",
+		audio.DeviceSwitchRequest{InputDeviceID: 145, SampleRate: 48000})
+	var response audio.DeviceSwitchResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode switch-devices response: %v", err)
+	}
+	if !response.IsAudioReady || response.PID == 0 || response.ProcessRestarted {
+		t.Fatalf("Expected a fresh start, got %+v", response)
+	}
+	if !audio.Reconfig.IsRunning() {
+		t.Error("Expected the reconfiguration manager to track the running process")
+	}
+}
+
+// assertEngineState checks the reconfiguration manager agrees with the global process
+func assertEngineState(t *testing.T, wantRunning bool) {
+	t.Helper()
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	running := process != nil && process.IsRunning()
+	if running != wantRunning || audio.Reconfig.IsRunning() != wantRunning {
+		t.Fatalf("Expected running=%v, process running=%v, manager running=%v",
+			wantRunning, running, audio.Reconfig.IsRunning())
+	}
+	if !wantRunning {
+		return
+	}
+	current := audio.Reconfig.GetCurrentConfig()
+	if current == nil || *current != process.ActualConfig() {
+		t.Errorf("Manager config %+v does not match the process config %+v", current, process.ActualConfig())
+	}
+}
+
+// TestLifecycleStateConsistency verifies every start path and stop keep the manager and process in step
+func TestLifecycleStateConsistency(t *testing.T) {
+	starts := map[string]func(t *testing.T, router http.Handler){
+		"start": startFakeAudio,
+		"switch-devices": func(t *testing.T, router http.Handler) {
+			postJSON(t, router, "/api/audio/switch-devices",
+				audio.DeviceSwitchRequest{InputDeviceID: 145, SampleRate: 48000})
+		},
+		"resume": func(t *testing.T, router http.Handler) {
+			if err := audio.SaveLastConfig(audio.AudioConfig{SampleRate: 48000, BufferSize: 256}); err != nil {
+				t.Fatalf("Failed to save config: %v", err)
+			}
+			if resumed, err := resumeAudio(); !resumed || err != nil {
+				t.Fatalf("Expected resume, got %v, %v", resumed, err)
+			}
+		},
+	}
+
+	for name, start := range starts {
+		t.Run(name, func(t *testing.T) {
+			useFakeAudioHost(t)
+			router := setupRoutes()
+
+			start(t, router)
+			assertEngineState(t, true)
+
+			if w := postJSON(t, router, "/api/audio/stop", nil); w.Code != http.StatusOK {
+				t.Fatalf("Stop failed with status %d: %s", w.Code, w.Body.String())
+			}
+			assertEngineState(t, false)
+		})
+	}
+}
+
+// TestAudioHostEnvironmentAndDir verifies audio-host is launched with the configured env and working directory
+func TestAudioHostEnvironmentAndDir(t *testing.T) {
+	useFakeAudioHost(t)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to resolve temp dir: %v", err)
+	}
+	originalDir, originalEnv := audio.AudioHostDir, audio.AudioHostEnv
+	audio.AudioHostDir = dir
+	audio.AudioHostEnv = []string{"RACKLESS_TEST_VAR=from-launcher"}
+	t.Cleanup(func() { audio.AudioHostDir, audio.AudioHostEnv = originalDir, originalEnv })
+
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	if output := sendCommand(t, router, "getenv RACKLESS_TEST_VAR"); !strings.Contains(output, "from-launcher") {
+		t.Errorf("Expected audio-host to see the configured env var, got %q", output)
+	}
+	if output := sendCommand(t, router, "pwd"); !strings.Contains(output, dir) {
+		t.Errorf("Expected audio-host to run in %s, got %q", dir, output)
+	}
+}
+
+// TestStartCircuitBreakerTrips verifies repeated launch failures answer 503 instead of spawning again
+func TestStartCircuitBreakerTrips(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv(audio.AudioHostPathEnv, filepath.Join(t.TempDir(), "missing-audio-host"))
+
+	router := setupRoutes()
+	request := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}}
+	for i := 0; i < audio.DefaultBreakerThreshold; i++ {
+		if w := postJSON(t, router, "/api/audio/start", request); w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected launch failure %d to answer 500, got %d: %s", i+1, w.Code, w.Body.String())
+		}
+	}
+
+	w := postJSON(t, router, "/api/audio/start", request)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 once the breaker trips, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") != "30" {
+		t.Errorf("Expected Retry-After: 30, got %q", w.Header().Get("Retry-After"))
+	}
+}
+
+// postWithIdempotencyKey sends a JSON request carrying an Idempotency-Key header
+func postWithIdempotencyKey(t *testing.T, router http.Handler, path, key string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+	req := httptest.NewRequest("POST", path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestStartIdempotencyKeyReplays verifies a retried start with the same key returns the original response
+func TestStartIdempotencyKeyReplays(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	request := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}}
+
+	first := postWithIdempotencyKey(t, router, "/api/audio/start", "start-retry-1", request)
+	if first.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", first.Code, first.Body.String())
+	}
+	pid := currentProcess(t).GetPID()
+
+	second := postWithIdempotencyKey(t, router, "/api/audio/start", "start-retry-1", request)
+	if second.Code != http.StatusOK {
+		t.Fatalf("Expected the retried start to replay 200, got %d: %s", second.Code, second.Body.String())
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("Expected the cached response %q, got %q", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected the replayed response to be marked")
+	}
+	if got := currentProcess(t).GetPID(); got != pid {
+		t.Errorf("Expected the original process %d to keep running, got %d", pid, got)
+	}
+
+	if w := postWithIdempotencyKey(t, router, "/api/audio/start", "start-retry-2", request); w.Code != http.StatusConflict {
+		t.Errorf("Expected a new key to conflict with the running process, got %d", w.Code)
+	}
+}
+
+// TestStartIdempotencyKeyAcrossAPIVersions verifies a key used under /api/v1
+// replays when retried through the legacy alias
+func TestStartIdempotencyKeyAcrossAPIVersions(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	request := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}}
+
+	first := postWithIdempotencyKey(t, router, "/api/v1/audio/start", "start-versions-1", request)
+	if first.Code != http.StatusOK {
+		t.Fatalf("Start failed with status %d: %s", first.Code, first.Body.String())
+	}
+
+	legacy := postWithIdempotencyKey(t, router, "/api/audio/start", "start-versions-1", request)
+	if legacy.Code != http.StatusOK || legacy.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("Expected the legacy retry to replay, got %d: %s", legacy.Code, legacy.Body.String())
+	}
+	if legacy.Header().Get("Deprecation") != "true" {
+		t.Error("Expected the legacy replay to keep its own Deprecation header")
+	}
+}
+
+// TestStartIdempotencyKeyRetriesAfterConflict verifies a 409 from a busy
+// lifecycle isn't replayed, so the same key succeeds once the engine settles
+func TestStartIdempotencyKeyRetriesAfterConflict(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+	request := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}}
+
+	if err := audio.Lifecycle.Begin(audio.StateSwitching); err != nil {
+		t.Fatalf("Failed to enter switching state: %v", err)
+	}
+	busy := postWithIdempotencyKey(t, router, "/api/audio/start", "start-busy-1", request)
+	audio.Lifecycle.End()
+	if busy.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 while switching, got %d: %s", busy.Code, busy.Body.String())
+	}
+	if busy.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After on the conflict")
+	}
+
+	retry := postWithIdempotencyKey(t, router, "/api/audio/start", "start-busy-1", request)
+	if retry.Code != http.StatusOK {
+		t.Fatalf("Expected the retry to start audio, got %d: %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("Expected the retry to run rather than replay the conflict")
+	}
+
+	replay := postWithIdempotencyKey(t, router, "/api/audio/start", "start-busy-1", request)
+	if replay.Code != http.StatusOK || replay.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("Expected the successful start to replay, got %d", replay.Code)
+	}
+	if replay.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected replayed headers, got Content-Type %q", replay.Header().Get("Content-Type"))
+	}
+}
+
+// getReady fetches /api/ready and returns the status and decoded body
+func getReady(t *testing.T, router http.Handler) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/ready", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode readiness: %v", err)
+	}
+	return w.Code, body
+}
+
+// TestReadinessTracksAudioHostBinary verifies readiness fails once the audio-host binary is missing
+func TestReadinessTracksAudioHostBinary(t *testing.T) {
+	useFakeAudioHost(t)
+	router := setupRoutes()
+
+	if status, body := getReady(t, router); status != http.StatusOK || body["ready"] != true {
+		t.Fatalf("Expected ready with the fake audio-host, got %d: %v", status, body)
+	}
+
+	t.Setenv(audio.AudioHostPathEnv, filepath.Join(t.TempDir(), "missing-audio-host"))
+	status, body := getReady(t, router)
+	if status != http.StatusServiceUnavailable || body["ready"] != false {
+		t.Fatalf("Expected 503 without an audio-host binary, got %d: %v", status, body)
+	}
+	host, _ := body["audioHost"].(map[string]interface{})
+	if !strings.Contains(fmt.Sprint(host["error"]), "not found") {
+		t.Errorf("Expected a not found reason, got %v", host)
+	}
+}
+
+// getAudioCapabilities fetches /api/audio/capabilities
+func getAudioCapabilities(t *testing.T, router http.Handler) (known bool, commands []string) {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/audio/capabilities", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Capabilities failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		Known    bool     `json:"known"`
+		Commands []string `json:"commands"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode capabilities: %v", err)
+	}
+	return response.Known, response.Commands
+}
+
+// TestAudioCapabilitiesListCommands verifies the command list queried at startup is exposed
+func TestAudioCapabilitiesListCommands(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_COMMANDS", "start stop status waveform")
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	known, commands := getAudioCapabilities(t, router)
+	if !known || !slices.Equal(commands, []string{"start", "stop", "status", "waveform"}) {
+		t.Errorf("Expected the fake host's command list, got known=%v %v", known, commands)
+	}
+	if process := currentProcess(t); !process.Supports("waveform") || process.Supports("monitor") {
+		t.Error("Expected Supports to follow the reported command list")
+	}
+}
+
+// TestAudioCapabilitiesLegacyHost verifies a host without the commands query reports an unknown list
+func TestAudioCapabilitiesLegacyHost(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_COMMANDS", "none")
+	router := setupRoutes()
+	startFakeAudio(t, router)
+
+	if known, commands := getAudioCapabilities(t, router); known || len(commands) != 0 {
+		t.Errorf("Expected an unknown command list, got known=%v %v", known, commands)
+	}
+}
+
+// TestLateCommandsResponseDiscarded verifies a host that answers "commands"
+// after the timeout doesn't hand its late reply to the next command, and that
+// the same binary isn't queried again on the next start
+func TestLateCommandsResponseDiscarded(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_COMMANDS", "late")
+	originalTimeouts, originalLogSize := audio.Timeouts, audio.CommandLogSize
+	t.Cleanup(func() { audio.Timeouts, audio.CommandLogSize = originalTimeouts, originalLogSize })
+	audio.Timeouts.Command = 250 * time.Millisecond
+	audio.CommandLogSize = 16
+	router := setupRoutes()
+
+	startFakeAudio(t, router)
+	if output := sendCommand(t, router, "ping"); output != "OK: pong" {
+		t.Fatalf("Expected the first command after start to get its own reply, got %q", output)
+	}
+
+	if w := postJSON(t, router, "/api/audio/stop", nil); w.Code != http.StatusOK {
+		t.Fatalf("Stop failed with status %d: %s", w.Code, w.Body.String())
+	}
+	startFakeAudio(t, router)
+	for _, entry := range currentProcess(t).CommandLog() {
+		if entry.Command == "commands" {
+			t.Error("Expected the legacy binary not to be queried again")
+		}
+	}
+	if known, _ := getAudioCapabilities(t, router); known {
+		t.Error("Expected the command list to stay unknown")
+	}
+}
+
+// TestReadyTimeoutConfigurable verifies a slow device open succeeds only when the READY timeout allows it
+func TestReadyTimeoutConfigurable(t *testing.T) {
+	useFakeAudioHost(t)
+	t.Setenv("FAKE_AUDIO_HOST_READY_DELAY", "300ms")
+	original := audio.Timeouts
+	t.Cleanup(func() { audio.Timeouts = original })
+	router := setupRoutes()
+	request := audio.StartAudioRequest{Config: audio.AudioConfig{SampleRate: 48000}}
+
+	audio.Timeouts.Ready = 50 * time.Millisecond
+	if w := postJSON(t, router, "/api/audio/start", request); w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected the short READY timeout to fail the start, got %d: %s", w.Code, w.Body.String())
+	}
+
+	audio.Timeouts.Ready = 5 * time.Second
+	startFakeAudio(t, router)
+}
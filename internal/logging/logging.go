@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats supported by Setup
+const (
+	FormatText = "text" // Human-readable key=value output for development
+	FormatJSON = "json" // One JSON object per line for machine parsing
+)
+
+// level holds the active log level shared by every logger created by Setup
+var level = new(slog.LevelVar)
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (must be debug, info, warn or error)", name)
+	}
+}
+
+// New creates a logger writing to w in the given format, filtered by the shared level
+func New(w io.Writer, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level}
+
+	switch format {
+	case "", FormatText:
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (must be text or json)", format)
+	}
+}
+
+// Setup installs the default slog logger writing to w with the given level and format
+func Setup(w io.Writer, levelName, format string) error {
+	parsed, err := ParseLevel(levelName)
+	if err != nil {
+		return err
+	}
+
+	logger, err := New(w, format)
+	if err != nil {
+		return err
+	}
+
+	level.Set(parsed)
+	slog.SetDefault(logger)
+	return nil
+}
+
+// SetLevel changes the active log level at runtime for every logger created by Setup
+func SetLevel(name string) error {
+	parsed, err := ParseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(parsed)
+	return nil
+}
+
+// Level returns the name of the active log level
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestErrorLevelSuppressesInfo verifies a LogLevel of "error" drops info logs
+func TestErrorLevelSuppressesInfo(t *testing.T) {
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "error", FormatText); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	slog.Info("devices loaded", "count", 3)
+	if buf.Len() != 0 {
+		t.Fatalf("Expected info log to be suppressed, got: %s", buf.String())
+	}
+
+	slog.Error("audio-host failed", "err", "boom")
+	if !strings.Contains(buf.String(), "audio-host failed") {
+		t.Fatalf("Expected error log to be written, got: %s", buf.String())
+	}
+}
+
+// TestJSONFormat verifies JSON output carries structured attributes
+func TestJSONFormat(t *testing.T) {
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "info", FormatJSON); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	slog.Info("audio-host started", "pid", 4242)
+	if !strings.Contains(buf.String(), `"pid":4242`) {
+		t.Errorf("Expected JSON attribute in output, got: %s", buf.String())
+	}
+}
+
+// TestParseLevel verifies level names and rejection of unknown values
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"info", slog.LevelInfo, false},
+		{"", slog.LevelInfo, false},
+		{"WARN", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", slog.LevelInfo, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestSetLevelAppliesLive verifies changing the level takes effect without re-running Setup
+func TestSetLevelAppliesLive(t *testing.T) {
+	original := slog.Default()
+	defer slog.SetDefault(original)
+
+	var buf bytes.Buffer
+	if err := Setup(&buf, "info", FormatText); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	slog.Debug("before change")
+	if buf.Len() != 0 {
+		t.Fatalf("Expected debug log to be suppressed at info, got: %s", buf.String())
+	}
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel failed: %v", err)
+	}
+	if Level() != "debug" {
+		t.Errorf("Expected level debug, got %s", Level())
+	}
+
+	slog.Debug("after change")
+	if !strings.Contains(buf.String(), "after change") {
+		t.Fatalf("Expected debug log after SetLevel, got: %s", buf.String())
+	}
+
+	if err := SetLevel("loud"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+	if Level() != "debug" {
+		t.Errorf("Expected level to stay debug after invalid update, got %s", Level())
+	}
+}
@@ -1,16 +1,29 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"math"
 	"net"
 	"net/http"
+	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/shaban/rackless/audio"
 	"github.com/shaban/rackless/internal/debug"
+	"github.com/shaban/rackless/internal/logging"
 )
 
 // ConfigChangeRequest represents a request to change audio configuration
@@ -35,10 +48,16 @@ type ConfigChangeResponse struct {
 
 // Sample rate validation functions
 func validateSampleRate(config audio.AudioConfig) error {
-	sampleRate := int(config.SampleRate)
+	sampleRate := audio.SampleRate(config.SampleRate)
+	devices := audio.Devices()
+
+	// Headless machines (CI, SSH sessions) have no output to render to
+	if len(devices.AudioOutput) == 0 {
+		return fmt.Errorf("no output device available")
+	}
 
 	// Check output device sample rate compatibility
-	for _, device := range audio.Data.Devices.AudioOutput {
+	for _, device := range devices.AudioOutput {
 		if device.IsDefault {
 			// Check if default output device is online
 			if !device.IsOnline {
@@ -46,16 +65,8 @@ func validateSampleRate(config audio.AudioConfig) error {
 					device.DeviceID, device.Name)
 			}
 
-			supported := false
-			for _, supportedRate := range device.SupportedSampleRates {
-				if supportedRate == sampleRate {
-					supported = true
-					break
-				}
-			}
-			if !supported {
-				return fmt.Errorf("output device %d (%s) does not support %d Hz. Supported rates: %v",
-					device.DeviceID, device.Name, sampleRate, device.SupportedSampleRates)
+			if !sampleRate.SupportedBy(device.SupportedSampleRates) {
+				return unsupportedRateError("output", device, sampleRate)
 			}
 			break
 		}
@@ -64,7 +75,7 @@ func validateSampleRate(config audio.AudioConfig) error {
 	// Check input device sample rate compatibility if specified
 	if config.AudioInputDeviceID != 0 {
 		found := false
-		for _, device := range audio.Data.Devices.AudioInput {
+		for _, device := range devices.AudioInput {
 			if device.DeviceID == config.AudioInputDeviceID {
 				found = true
 
@@ -74,16 +85,8 @@ func validateSampleRate(config audio.AudioConfig) error {
 						device.DeviceID, device.Name)
 				}
 
-				supported := false
-				for _, supportedRate := range device.SupportedSampleRates {
-					if supportedRate == sampleRate {
-						supported = true
-						break
-					}
-				}
-				if !supported {
-					return fmt.Errorf("input device %d (%s) does not support %d Hz. Supported rates: %v",
-						device.DeviceID, device.Name, sampleRate, device.SupportedSampleRates)
+				if !sampleRate.SupportedBy(device.SupportedSampleRates) {
+					return unsupportedRateError("input", device, sampleRate)
 				}
 				break
 			}
@@ -96,13 +99,25 @@ func validateSampleRate(config audio.AudioConfig) error {
 	return nil
 }
 
+// unsupportedRateError explains that device can't run at rate, suggesting the
+// closest rate it does support
+func unsupportedRateError(direction string, device audio.AudioDevice, rate audio.SampleRate) error {
+	if nearest, ok := rate.NearestSupported(device.SupportedSampleRates); ok {
+		return fmt.Errorf("%s device %d (%s) does not support %d Hz (nearest supported: %d Hz). Supported rates: %v",
+			direction, device.DeviceID, device.Name, rate, nearest, device.SupportedSampleRates)
+	}
+	return fmt.Errorf("%s device %d (%s) does not support %d Hz. Supported rates: %v",
+		direction, device.DeviceID, device.Name, rate, device.SupportedSampleRates)
+}
+
 func findCompatibleSampleRate(inputDeviceID, outputDeviceID int) (int, error) {
+	devices := audio.Devices()
 	var inputSupportedRates []int
 	var outputSupportedRates []int
 
 	// Get input device supported rates
 	if inputDeviceID != 0 {
-		for _, device := range audio.Data.Devices.AudioInput {
+		for _, device := range devices.AudioInput {
 			if device.DeviceID == inputDeviceID {
 				inputSupportedRates = device.SupportedSampleRates
 				break
@@ -115,7 +130,7 @@ func findCompatibleSampleRate(inputDeviceID, outputDeviceID int) (int, error) {
 
 	// Get output device supported rates (use default if not specified)
 	if outputDeviceID != 0 {
-		for _, device := range audio.Data.Devices.AudioOutput {
+		for _, device := range devices.AudioOutput {
 			if device.DeviceID == outputDeviceID {
 				outputSupportedRates = device.SupportedSampleRates
 				break
@@ -123,7 +138,7 @@ func findCompatibleSampleRate(inputDeviceID, outputDeviceID int) (int, error) {
 		}
 	} else {
 		// Use default output device
-		for _, device := range audio.Data.Devices.AudioOutput {
+		for _, device := range devices.AudioOutput {
 			if device.IsDefault {
 				outputSupportedRates = device.SupportedSampleRates
 				break
@@ -143,31 +158,83 @@ func findCompatibleSampleRate(inputDeviceID, outputDeviceID int) (int, error) {
 			commonRates = append(commonRates, outputRate)
 		} else {
 			// Check if input device supports this rate
-			for _, inputRate := range inputSupportedRates {
-				if inputRate == outputRate {
-					commonRates = append(commonRates, outputRate)
-					break
-				}
+			if audio.SampleRate(outputRate).SupportedBy(inputSupportedRates) {
+				commonRates = append(commonRates, outputRate)
 			}
 		}
 	}
 
-	if len(commonRates) == 0 {
+	// Prefer standard rates, falling back to the first common rate
+	preferred, ok := audio.PreferredSampleRate(commonRates)
+	if !ok {
 		return 0, fmt.Errorf("no compatible sample rates found between devices")
 	}
+	if !preferred.IsStandard() {
+		slog.Info("devices share no standard sample rate", "sampleRate", int(preferred), "commonRates", commonRates)
+	}
+	return int(preferred), nil
+}
 
-	// Prefer standard rates in order: 44100, 48000, 96000, 192000
-	preferredRates := []int{44100, 48000, 96000, 192000}
-	for _, preferred := range preferredRates {
-		for _, common := range commonRates {
-			if common == preferred {
-				return preferred, nil
-			}
+// Clock relationships between the selected input and output devices
+const (
+	ClockNoInput     = "no-input"    // Output only, nothing to keep in sync
+	ClockSameDevice  = "same-device" // Input and output share one device clock
+	ClockAggregate   = "aggregate"   // An aggregate device keeps sub-devices in sync
+	ClockIndependent = "independent" // Separate clocks that will drift apart
+)
+
+// CompatibilityReport describes whether an input/output pair can run glitch-free together
+type CompatibilityReport struct {
+	InputDeviceID      int    `json:"inputDeviceId"`
+	OutputDeviceID     int    `json:"outputDeviceId"`
+	Clock              string `json:"clock"`
+	GlitchFree         bool   `json:"glitchFree"`
+	RequiresResampling bool   `json:"requiresResampling"`
+	Message            string `json:"message"`
+}
+
+// checkDeviceCompatibility reports whether the input and output devices share a clock.
+// An outputDeviceID of 0 means the default output device.
+func checkDeviceCompatibility(inputDeviceID, outputDeviceID int) CompatibilityReport {
+	devices := audio.Devices()
+	var output *audio.AudioDevice
+	for _, device := range devices.AudioOutput {
+		if (outputDeviceID != 0 && device.DeviceID == outputDeviceID) ||
+			(outputDeviceID == 0 && (device.IsDefault || device.DeviceID == devices.Defaults.DefaultOutput)) {
+			output = &device
+			break
 		}
 	}
 
-	// If no preferred rate found, return the first common rate
-	return commonRates[0], nil
+	report := CompatibilityReport{
+		InputDeviceID:  inputDeviceID,
+		OutputDeviceID: outputDeviceID,
+	}
+	if output != nil {
+		report.OutputDeviceID = output.DeviceID
+	}
+
+	switch {
+	case inputDeviceID == 0:
+		report.Clock = ClockNoInput
+		report.GlitchFree = true
+		report.Message = "No input device selected - output runs on its own clock"
+	case inputDeviceID == report.OutputDeviceID && output != nil && output.IsAggregate():
+		report.Clock = ClockAggregate
+		report.GlitchFree = true
+		report.Message = fmt.Sprintf("Aggregate device %s keeps its sub-devices in sync", output.Name)
+	case inputDeviceID == report.OutputDeviceID:
+		report.Clock = ClockSameDevice
+		report.GlitchFree = true
+		report.Message = "Input and output share the same device clock"
+	default:
+		report.Clock = ClockIndependent
+		report.RequiresResampling = true
+		report.Message = "Input and output are different devices without a shared clock - " +
+			"expect drift unless they are combined into an aggregate device"
+	}
+
+	return report
 }
 
 // Device testing function for simplified boolean approach
@@ -179,6 +246,12 @@ func testDeviceConfiguration(config audio.AudioConfig) (bool, string, string) {
 			"Please select compatible audio devices and sample rate"
 	}
 
+	if err := audio.PreStartCheck(config); err != nil {
+		return false,
+			fmt.Sprintf("Device unavailable: %v", err),
+			"Close other applications using the device or select a different one"
+	}
+
 	// Step 2: Try to actually start audio-host with these parameters
 	// This is the real test - can we initialize the audio system?
 	tempProcess, err := audio.StartAudioHostProcess(config)
@@ -194,60 +267,264 @@ func testDeviceConfiguration(config audio.AudioConfig) (bool, string, string) {
 	return true, "", ""
 }
 
-// Device switching function - stops current audio-host and starts new one
-func switchAudioDevices(config audio.AudioConfig) (bool, string, string, bool, int) {
-	// Step 1: Check if audio-host is currently running
+// rememberAudioConfig persists a successfully started config so it can be resumed after a restart
+func rememberAudioConfig(config audio.AudioConfig) {
+	if err := audio.SaveLastConfig(config); err != nil {
+		slog.Warn("failed to save audio configuration", "err", err)
+	}
+}
+
+// beginLifecycle enters a lifecycle transition, answering 409 with a
+// Retry-After header when another start, stop or switch is still in flight
+func beginLifecycle(w http.ResponseWriter, transition audio.LifecycleState) bool {
+	err := audio.Lifecycle.Begin(transition)
+	if err == nil {
+		return true
+	}
+
+	slog.Warn("lifecycle request rejected", "transition", transition.String(), "err", err)
+	response := map[string]interface{}{
+		"success": false,
+		"error":   err.Error(),
+	}
+	var busy *audio.LifecycleBusyError
+	if errors.As(err, &busy) {
+		response["state"] = busy.State.String()
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(audio.LifecycleRetryAfter/time.Second)))
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(response)
+	return false
+}
+
+// resumeAudio starts audio-host with the last saved configuration, after
+// checking that its devices are still present. It returns false without an
+// error when there is nothing to resume.
+func resumeAudio() (bool, error) {
+	if err := audio.Lifecycle.Begin(audio.StateStarting); err != nil {
+		return false, err
+	}
+	defer audio.Lifecycle.End()
+
+	saved, err := audio.LoadLastConfig()
+	if err != nil || saved == nil {
+		return false, err
+	}
+
+	config, err := saved.Reconcile(audio.Devices())
+	if err != nil {
+		return false, err
+	}
+	if err := validateSampleRate(config); err != nil {
+		return false, err
+	}
+	if err := audio.PreStartCheck(config); err != nil {
+		return false, err
+	}
+
+	process, err := audio.Reconfig.Start(config)
+	if err != nil {
+		return false, err
+	}
+
+	slog.Info("resumed audio with saved configuration",
+		"pid", process.GetPID(), "sampleRate", config.SampleRate, "inputDevice", config.AudioInputDeviceID)
+	return true, nil
+}
+
+// startSavedAudio is the start path used by autoStartAudio; tests replace it
+var startSavedAudio = resumeAudio
+
+// autoStartAudio resumes the saved audio configuration at boot when enabled and logs the outcome
+func autoStartAudio(enabled bool) {
+	if !enabled {
+		slog.Info("audio auto-start disabled")
+		return
+	}
+
+	resumed, err := startSavedAudio()
+	switch {
+	case err != nil:
+		slog.Warn("audio auto-start skipped", "err", err)
+	case !resumed:
+		slog.Info("audio auto-start skipped: no saved configuration")
+	}
+}
+
+// switchAudioDevices validates config and applies it through the
+// reconfiguration manager, which restarts audio-host when the change needs it
+// and starts it when nothing is running
+func switchAudioDevices(config audio.AudioConfig) audio.DeviceSwitchResponse {
 	audio.Mutex.RLock()
 	wasRunning := audio.Process != nil && audio.Process.IsRunning()
-	currentProcess := audio.Process
 	audio.Mutex.RUnlock()
 
-	// Step 2: Stop current audio-host if running
-	if wasRunning {
-		log.Printf("🔄 Stopping current audio-host to switch devices...")
-		audio.Mutex.Lock()
-		audio.Process = nil
-		audio.Mutex.Unlock()
-
-		err := currentProcess.Stop()
-		if err != nil {
-			return false,
-				fmt.Sprintf("Failed to stop current audio-host: %v", err),
-				"Try manually stopping audio processes or restart the server",
-				wasRunning, 0
-		}
-		log.Printf("✅ Current audio-host stopped successfully")
+	response := audio.DeviceSwitchResponse{
+		NewConfig:              config,
+		PreviousProcessRunning: wasRunning,
 	}
 
-	// Step 3: Validate new configuration
+	// Validate before touching the running process so a bad request leaves audio alone
 	if err := validateSampleRate(config); err != nil {
-		return false,
-			fmt.Sprintf("New device configuration invalid: %v", err),
-			"Please select compatible audio devices and sample rate",
-			wasRunning, 0
+		response.ErrorMessage = fmt.Sprintf("New device configuration invalid: %v", err)
+		response.RequiredAction = "Please select compatible audio devices and sample rate"
+		return response
+	}
+	if err := audio.PreStartCheck(config); err != nil {
+		response.ErrorMessage = fmt.Sprintf("New device unavailable: %v", err)
+		response.RequiredAction = "Close other applications using the device or select a different one"
+		return response
 	}
 
-	// Step 4: Start audio-host with new configuration
-	log.Printf("🚀 Starting audio-host with new device configuration...")
-	newProcess, err := audio.StartAudioHostProcess(config)
+	result, err := audio.Reconfig.ApplyConfigChange(audio.ConfigChange{
+		NewConfig:      config,
+		ChangeReason:   "device switch",
+		RequireRunning: true,
+	})
+	if result != nil {
+		response.ChangeType = changeTypeToString(result.ChangeType)
+		response.Details = result
+	}
 	if err != nil {
-		return false,
-			fmt.Sprintf("Failed to start audio-host with new devices: %v", err),
-			"Check if new devices are available and not in use by other applications",
-			wasRunning, 0
+		response.ErrorMessage = fmt.Sprintf("Failed to switch devices: %v", err)
+		response.RequiredAction = "Check if new devices are available and not in use by other applications"
+		return response
+	}
+
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	response.IsAudioReady = process != nil && process.IsRunning()
+	if response.IsAudioReady {
+		response.PID = process.GetPID()
+		rememberAudioConfig(config)
+	}
+	response.ProcessRestarted = wasRunning && result.ProcessIDChanged
+
+	slog.Info("audio devices switched", "pid", response.PID, "changeType", response.ChangeType)
+	return response
+}
+
+// Request body limits. Bodies are small JSON documents; anything larger is a
+// misbehaving client.
+const (
+	maxCommandBodyBytes = 4 << 10  // audio-host commands, MIDI messages, log level
+	maxConfigBodyBytes  = 64 << 10 // audio configurations and device requests
+)
+
+// strictJSON makes request decoding reject unknown fields, so a typo such as
+// "sampleRat" fails loudly instead of being dropped (set by --strict-json)
+var strictJSON bool
+
+// decodeJSONBody decodes r's body into v, reading at most limit bytes. On
+// failure it writes 413 for an oversized body or a 400 error envelope naming
+// the offending field or byte offset, and returns false.
+func decodeJSONBody(w http.ResponseWriter, r *http.Request, v interface{}, limit int64) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	decoder := json.NewDecoder(r.Body)
+	if strictJSON {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("Request body exceeds %d bytes", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+			return false
+		}
+
+		response := map[string]interface{}{"success": false}
+		message, field, offset := describeJSONError(err)
+		response["error"] = message
+		if field != "" {
+			response["field"] = field
+		}
+		if offset > 0 {
+			response["offset"] = offset
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(response)
+		return false
+	}
+	return true
+}
+
+// describeJSONError turns a decode error into a client-facing message and,
+// where the decoder knows them, the offending field path and byte offset
+func describeJSONError(err error) (message, field string, offset int64) {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return fmt.Sprintf("Invalid JSON at byte %d: %v", syntaxErr.Offset, syntaxErr), "", syntaxErr.Offset
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return fmt.Sprintf("Invalid JSON: field %q must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value),
+			typeErr.Field, typeErr.Offset
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("Invalid JSON: expected %s, got %s", typeErr.Type, typeErr.Value), "", typeErr.Offset
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for DisallowUnknownFields
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		return fmt.Sprintf("Invalid JSON: unknown field %q", field), field, 0
+	case errors.Is(err, io.EOF):
+		return "Invalid JSON: request body is empty", "", 0
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return "Invalid JSON: unexpected end of input", "", 0
+	default:
+		return fmt.Sprintf("Invalid JSON: %v", err), "", 0
+	}
+}
+
+// computeETag hashes the JSON encoding of v into a weak ETag. It is weak
+// because gzipMiddleware may send the same representation compressed or not.
+// The payload is streamed into the hash so large responses aren't buffered.
+func computeETag(v interface{}) (string, error) {
+	// Device data ignores enumeration timestamps, so a refresh of unchanged
+	// hardware keeps clients' cached copy valid
+	if devices, ok := v.(audio.DevicesData); ok {
+		return fmt.Sprintf(`W/"%s"`, devices.Hash()[:32]), nil
+	}
+
+	hash := sha256.New()
+	if err := json.NewEncoder(hash).Encode(v); err != nil {
+		return "", err
 	}
+	return fmt.Sprintf(`W/"%x"`, hash.Sum(nil)[:16]), nil
+}
 
-	// Step 5: Store the new process
-	audio.Mutex.Lock()
-	audio.Process = newProcess
-	audio.Mutex.Unlock()
+// etagMatches reports whether an If-None-Match header matches the given ETag,
+// using the weak comparison If-None-Match calls for
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotModified sets ETag and cache headers for v and answers 304 Not Modified
+// when the client already has the current version. It returns true if the
+// response has been written.
+func checkNotModified(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	etag, err := computeETag(v)
+	if err != nil {
+		return false
+	}
 
-	// Update reconfiguration system
-	audio.Reconfig.SetCurrentConfig(config)
-	audio.Reconfig.SetRunning(true)
+	// Clients may cache but must revalidate every time
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	addVary(w.Header(), "Accept-Encoding")
 
-	log.Printf("✅ Audio devices switched successfully - new PID %d", newProcess.GetPID())
-	return true, "", "", wasRunning, newProcess.GetPID()
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
 }
 
 // API Handlers
@@ -255,17 +532,61 @@ func handleDevices(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*") // For WASM development
 
-	if err := json.NewEncoder(w).Encode(audio.Data.Devices); err != nil {
+	// One snapshot for both the ETag and the body
+	devices := audio.Devices()
+	if checkNotModified(w, r, devices) {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(devices); err != nil {
 		http.Error(w, "Failed to encode devices data", http.StatusInternalServerError)
 		return
 	}
 }
 
+// handleDeviceCounts serves per-category device counts, for UI badges that
+// don't need the full device payload
+func handleDeviceCounts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	counts := audio.Devices().Counts()
+
+	if err := json.NewEncoder(w).Encode(counts); err != nil {
+		http.Error(w, "Failed to encode device counts", http.StatusInternalServerError)
+		return
+	}
+}
+
+func handleDeviceCapabilities(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	uid := r.PathValue("uid")
+	capabilities, found := audio.GetDeviceCapabilities(audio.Devices(), uid)
+	if !found {
+		http.Error(w, fmt.Sprintf("Device %s not found", uid), http.StatusNotFound)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(capabilities); err != nil {
+		http.Error(w, "Failed to encode device capabilities", http.StatusInternalServerError)
+		return
+	}
+}
+
 func handlePlugins(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*") // For WASM development
 
-	if err := json.NewEncoder(w).Encode(audio.Data.Plugins); err != nil {
+	plugins := audio.Plugins()
+	if checkNotModified(w, r, plugins) {
+		return
+	}
+
+	// Stream straight to the client - the plugin list can be hundreds of
+	// plugins with many parameters each, so avoid buffering it in memory
+	if err := json.NewEncoder(w).Encode(plugins); err != nil {
 		http.Error(w, "Failed to encode plugins data", http.StatusInternalServerError)
 		return
 	}
@@ -275,21 +596,111 @@ func handlePlugin(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	// Extract plugin ID from path: /api/plugins/{id}
-	path := strings.TrimPrefix(r.URL.Path, "/api/plugins/")
-	pluginID, err := strconv.Atoi(path)
+	plugin, ok := lookupPlugin(w, r)
+	if !ok {
+		return
+	}
+
+	// ?summary=true omits the parameter list; fetch it via /parameters instead
+	var body interface{} = plugin
+	if summary, _ := strconv.ParseBool(r.URL.Query().Get("summary")); summary {
+		body = plugin.Summary()
+	}
+
+	if checkNotModified(w, r, body) {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		http.Error(w, "Failed to encode plugin data", http.StatusInternalServerError)
+		return
+	}
+}
+
+// lookupPlugin resolves the {id} path value to a plugin, writing an error response if it can't
+func lookupPlugin(w http.ResponseWriter, r *http.Request) (audio.Plugin, bool) {
+	pluginID, err := strconv.Atoi(r.PathValue("id"))
 	if err != nil {
 		http.Error(w, "Invalid plugin ID", http.StatusBadRequest)
-		return
+		return audio.Plugin{}, false
 	}
 
-	if pluginID < 0 || pluginID >= len(audio.Data.Plugins) {
+	plugins := audio.Plugins()
+	if pluginID < 0 || pluginID >= len(plugins) {
 		http.Error(w, "Plugin not found", http.StatusNotFound)
+		return audio.Plugin{}, false
+	}
+	return plugins[pluginID], true
+}
+
+// Parameter pagination limits
+const (
+	defaultParameterPageSize = 50
+	maxParameterPageSize     = 500
+)
+
+// PluginParametersPage is one page of a plugin's parameters
+type PluginParametersPage struct {
+	Total      int                     `json:"total"`
+	Offset     int                     `json:"offset"`
+	Limit      int                     `json:"limit"`
+	Parameters []audio.PluginParameter `json:"parameters"`
+}
+
+// parsePagination reads offset and limit query parameters, applying defaults and bounds
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (int, int, error) {
+	offset, limit := 0, defaultLimit
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q (must be a non-negative integer)", value)
+		}
+		offset = parsed
+	}
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxLimit {
+			return 0, 0, fmt.Errorf("invalid limit %q (must be 1-%d)", value, maxLimit)
+		}
+		limit = parsed
+	}
+
+	return offset, limit, nil
+}
+
+func handlePluginParameters(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	plugin, ok := lookupPlugin(w, r)
+	if !ok {
 		return
 	}
 
-	if err := json.NewEncoder(w).Encode(audio.Data.Plugins[pluginID]); err != nil {
-		http.Error(w, "Failed to encode plugin data", http.StatusInternalServerError)
+	offset, limit, err := parsePagination(r, defaultParameterPageSize, maxParameterPageSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	total := len(plugin.Parameters)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	page := PluginParametersPage{
+		Total:      total,
+		Offset:     offset,
+		Limit:      limit,
+		Parameters: plugin.Parameters[start:end],
+	}
+	if page.Parameters == nil {
+		page.Parameters = []audio.PluginParameter{}
+	}
+
+	if err := json.NewEncoder(w).Encode(page); err != nil {
+		http.Error(w, "Failed to encode plugin parameters", http.StatusInternalServerError)
 		return
 	}
 }
@@ -298,21 +709,160 @@ func handleServerData(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*") // For WASM development
 
-	if err := json.NewEncoder(w).Encode(audio.Data); err != nil {
+	// ?refresh=true re-enumerates devices and rescans plugins before responding
+	if refresh, _ := strconv.ParseBool(r.URL.Query().Get("refresh")); refresh {
+		ctx := r.Context()
+		if value := r.URL.Query().Get("timeout"); value != "" {
+			timeout, err := parseRefreshTimeout(value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		if err := refreshServerData(ctx); err != nil {
+			if r.Context().Err() != nil {
+				slog.Info("server data refresh abandoned by client", "err", err)
+				return
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				slog.Warn("server data refresh timed out", "err", err)
+				http.Error(w, fmt.Sprintf("Server data refresh timed out: %v", err), http.StatusGatewayTimeout)
+				return
+			}
+			slog.Error("server data refresh failed", "err", err)
+			http.Error(w, fmt.Sprintf("Failed to refresh server data: %v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Encode from a copy so a slow client can't hold up device and plugin reloads
+	audio.Mutex.RLock()
+	data := audio.Data
+	audio.Mutex.RUnlock()
+	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, "Failed to encode server data", http.StatusInternalServerError)
 		return
 	}
 }
 
+// maxRefreshTimeout caps the ?timeout= a client may ask a refresh to wait
+const maxRefreshTimeout = 2 * time.Minute
+
+// parseRefreshTimeout reads a refresh ?timeout= value such as "2s", clamping
+// it to maxRefreshTimeout
+func parseRefreshTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q (must be a positive duration such as 2s)", value)
+	}
+	return min(timeout, maxRefreshTimeout), nil
+}
+
+// Device enumeration at boot is retried so a transient CoreAudio failure
+// doesn't take the server down; tests replace loadDevices and the delay
+var (
+	deviceLoadAttempts   = 3
+	deviceLoadRetryDelay = 2 * time.Second
+	loadDevices          = audio.LoadDevicesContext
+)
+
+// degradedReason explains why the server is running without device data;
+// empty when healthy
+var (
+	degradedMu     sync.RWMutex
+	degradedReason string
+)
+
+func setDegraded(reason string) {
+	degradedMu.Lock()
+	defer degradedMu.Unlock()
+	degradedReason = reason
+}
+
+func degradedStatus() (bool, string) {
+	degradedMu.RLock()
+	defer degradedMu.RUnlock()
+	return degradedReason != "", degradedReason
+}
+
+// loadDevicesWithRetry runs device enumeration up to deviceLoadAttempts times
+func loadDevicesWithRetry() error {
+	var err error
+	for attempt := 1; attempt <= deviceLoadAttempts; attempt++ {
+		if err = loadDevices(context.Background()); err == nil {
+			return nil
+		}
+		slog.Warn("device enumeration failed", "attempt", attempt, "attempts", deviceLoadAttempts, "err", err)
+		if attempt < deviceLoadAttempts {
+			time.Sleep(deviceLoadRetryDelay)
+		}
+	}
+	return err
+}
+
+// initDevices loads devices at boot. If enumeration keeps failing the server
+// starts with an empty device set in degraded mode instead of exiting; a
+// later refresh can recover.
+func initDevices() {
+	if err := loadDevicesWithRetry(); err != nil {
+		slog.Error("starting without devices (degraded mode)", "err", err)
+		audio.Mutex.Lock()
+		audio.Data.Devices = audio.DevicesData{}
+		audio.Mutex.Unlock()
+		setDegraded(fmt.Sprintf("device enumeration failed: %v", err))
+		return
+	}
+	setDegraded("")
+}
+
+// initServerData sets up the audio package and loads device and plugin
+// information at boot. Device failures leave the server degraded; only a
+// plugin failure is returned.
+func initServerData() error {
+	audio.Initialize()
+	initDevices()
+	if err := audio.LoadPlugins(); err != nil {
+		return fmt.Errorf("failed to load plugins: %w", err)
+	}
+	return nil
+}
+
+// refreshServerData re-runs device and plugin enumeration, giving up when ctx
+// is cancelled
+func refreshServerData(ctx context.Context) error {
+	if err := loadDevices(ctx); err != nil {
+		return err
+	}
+	setDegraded("")
+	return audio.LoadPluginsContext(ctx)
+}
+
 func handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	plugins := len(audio.Plugins())
+
+	devices := audio.Devices()
+
 	health := map[string]interface{}{
 		"status":    "healthy",
-		"devices":   len(audio.Data.Devices.AudioInput) + len(audio.Data.Devices.AudioOutput),
-		"plugins":   len(audio.Data.Plugins),
-		"timestamp": audio.Data.Devices.Timestamp,
+		"devices":   len(devices.AudioInput) + len(devices.AudioOutput),
+		"plugins":   plugins,
+		"timestamp": devices.Timestamp,
+	}
+
+	if !devices.Complete() {
+		health["deviceErrors"] = devices.Errors
+	}
+	if degraded, reason := degradedStatus(); degraded {
+		health["status"] = "degraded"
+		health["degraded"] = true
+		health["degradedReason"] = reason
 	}
 
 	if err := json.NewEncoder(w).Encode(health); err != nil {
@@ -321,6 +871,191 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleReady answers 200 when audio can be started: the audio-host binary
+// is runnable and device enumeration succeeded. Otherwise it answers 503.
+func handleReady(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	host := audio.CheckAudioHostBinary()
+	degraded, reason := degradedStatus()
+	ready := host.Runnable && !degraded
+
+	response := map[string]interface{}{
+		"ready":     ready,
+		"audioHost": host,
+	}
+	if degraded {
+		response["degradedReason"] = reason
+	}
+
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode readiness", http.StatusInternalServerError)
+		return
+	}
+}
+
+// launchAudio validates config and starts audio-host with it. On failure it
+// returns a nil process with the HTTP status and message to report.
+func launchAudio(config audio.AudioConfig) (*audio.AudioHostProcess, int, string) {
+	// Validate sample rate compatibility
+	if err := validateSampleRate(config); err != nil {
+		slog.Warn("sample rate validation failed", "err", err)
+		return nil, http.StatusBadRequest, fmt.Sprintf("Sample rate validation failed: %v", err)
+	}
+
+	// Catch devices that are offline or grabbed by another application
+	if err := audio.PreStartCheck(config); err != nil {
+		slog.Warn("pre-start check failed", "err", err)
+		var inUse *audio.DeviceInUseError
+		if errors.As(err, &inUse) {
+			return nil, http.StatusConflict, err.Error()
+		}
+		return nil, http.StatusBadRequest, err.Error()
+	}
+
+	// Start audio-host through the reconfiguration manager so it tracks the process
+	process, err := audio.Reconfig.Start(config)
+	if err != nil {
+		slog.Error("failed to start audio-host", "err", err)
+		audio.StartFailures.RecordFailure()
+		return nil, http.StatusInternalServerError, fmt.Sprintf("Failed to start audio-host: %v", err)
+	}
+	audio.StartFailures.RecordSuccess()
+	return process, http.StatusOK, ""
+}
+
+// Idempotency-Key support for start and stop: a retried request carrying
+// the same key replays the first response instead of conflicting with it
+const (
+	idempotencyTTL       = 10 * time.Minute
+	maxIdempotencyKeyLen = 255
+)
+
+// idempotentResponse is a completed response kept for replay. done is closed
+// once the first request finishes so concurrent retries can wait for it.
+type idempotentResponse struct {
+	done    chan struct{}
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// idempotentStatus reports whether a response is a final outcome worth
+// replaying. Conflicts, unavailability and server errors are transient, so a
+// retry with the same key must run again rather than get the old answer.
+func idempotentStatus(status int) bool {
+	switch {
+	case status >= 200 && status < 300:
+		return true
+	case status == http.StatusBadRequest, status == http.StatusRequestEntityTooLarge:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	idempotencyMu    sync.Mutex
+	idempotencyCache = map[string]*idempotentResponse{}
+)
+
+// idempotencyRecorder copies a response as it is written
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *idempotencyRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+// idempotent wraps a handler so requests with an Idempotency-Key header run
+// at most once per key within idempotencyTTL. Only final outcomes are kept
+// (see idempotentStatus), so a retry after a 409, 503 or 5xx runs again.
+func idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			http.Error(w, fmt.Sprintf("Idempotency-Key exceeds %d characters", maxIdempotencyKeyLen), http.StatusBadRequest)
+			return
+		}
+		// Key on the unversioned route so a retry through the legacy alias
+		// replays a request first made under /api/v1, and vice versa
+		cacheKey := r.Method + " " + unversionedPath(r.URL.Path) + " " + key
+
+		idempotencyMu.Lock()
+		now := time.Now()
+		for k, entry := range idempotencyCache {
+			if !entry.expires.IsZero() && now.After(entry.expires) {
+				delete(idempotencyCache, k)
+			}
+		}
+		entry, found := idempotencyCache[cacheKey]
+		if !found {
+			entry = &idempotentResponse{done: make(chan struct{})}
+			idempotencyCache[cacheKey] = entry
+		}
+		idempotencyMu.Unlock()
+
+		if found {
+			<-entry.done
+			if entry.status != 0 {
+				slog.Debug("replaying idempotent response", "path", r.URL.Path, "key", key)
+				for name, values := range entry.header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(entry.status)
+				w.Write(entry.body)
+				return
+			}
+			// The first attempt didn't reach a final outcome; run this one afresh
+			next(w, r)
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		defer func() {
+			idempotencyMu.Lock()
+			defer idempotencyMu.Unlock()
+			if idempotentStatus(rec.status) {
+				entry.status = rec.status
+				// Encoding and deprecation headers belong to this response's
+				// transfer and path; the replay gets its own from the middleware
+				entry.header = w.Header().Clone()
+				for _, name := range []string{"Content-Encoding", "Content-Length", "Vary", "Deprecation", "Link"} {
+					entry.header.Del(name)
+				}
+				entry.body = rec.body.Bytes()
+				entry.expires = time.Now().Add(idempotencyTTL)
+			} else {
+				delete(idempotencyCache, cacheKey)
+			}
+			close(entry.done)
+		}()
+		next(rec, r)
+	}
+}
+
 func handleStartAudio(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -330,6 +1065,11 @@ func handleStartAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !beginLifecycle(w, audio.StateStarting) {
+		return
+	}
+	defer audio.Lifecycle.End()
+
 	// Check if audio-host is already running
 	audio.Mutex.RLock()
 	if audio.Process != nil && audio.Process.IsRunning() {
@@ -344,19 +1084,30 @@ func handleStartAudio(w http.ResponseWriter, r *http.Request) {
 	}
 	audio.Mutex.RUnlock()
 
+	// Stop spawning processes while repeated launch failures have tripped the breaker
+	if err := audio.StartFailures.Allow(); err != nil {
+		var open *audio.BreakerOpenError
+		if errors.As(err, &open) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(open.RetryAfter.Seconds()))))
+		}
+		slog.Warn("start rejected by circuit breaker", "err", err)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(audio.StartAudioResponse{Success: false, Message: err.Error()})
+		return
+	}
+
 	var request audio.StartAudioRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSONBody(w, r, &request, maxConfigBodyBytes) {
 		return
 	}
 
 	config := request.Config
-	log.Printf("🎯 Starting audio with config: sample rate %.0f Hz, input device %d, buffer size %d",
-		config.SampleRate, config.AudioInputDeviceID, config.BufferSize)
+	slog.Info("starting audio",
+		"sampleRate", config.SampleRate, "inputDevice", config.AudioInputDeviceID, "bufferSize", config.BufferSize)
 
 	// Validate buffer size (professional audio range: 32-1024 samples)
 	if config.BufferSize != 0 && (config.BufferSize < 32 || config.BufferSize > 1024) {
-		log.Printf("❌ Invalid buffer size: %d (must be 32-1024 samples)", config.BufferSize)
+		slog.Warn("invalid buffer size", "bufferSize", config.BufferSize)
 		response := audio.StartAudioResponse{
 			Success: false,
 			Message: fmt.Sprintf("Invalid buffer size: %d (must be 32-1024 samples)", config.BufferSize),
@@ -366,51 +1117,47 @@ func handleStartAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set default buffer size if not specified (256 is good balance of latency vs stability)
-	if config.BufferSize == 0 {
-		config.BufferSize = 256
-		log.Printf("🔧 Using default buffer size: %d samples", config.BufferSize)
-	}
+	// Fill unset sample rate and buffer size from the configured defaults
+	config = config.WithDefaults()
 
-	// Validate sample rate compatibility
-	if err := validateSampleRate(config); err != nil {
-		log.Printf("❌ Sample rate validation failed: %v", err)
-		response := audio.StartAudioResponse{
-			Success: false,
-			Message: fmt.Sprintf("Sample rate validation failed: %v", err),
+	process, status, message := launchAudio(config)
+
+	// Safe mode: retry once with conservative defaults
+	fellBack := false
+	if process == nil && request.SafeMode {
+		fallback := audio.SafeModeConfig(audio.Devices())
+		slog.Warn("start failed, retrying in safe mode",
+			"err", message, "sampleRate", fallback.SampleRate, "inputDevice", fallback.AudioInputDeviceID)
+		if fallbackProcess, _, fallbackMessage := launchAudio(fallback); fallbackProcess != nil {
+			process, config, fellBack = fallbackProcess, fallback, true
+		} else {
+			message = fmt.Sprintf("%s; safe-mode fallback also failed: %s", message, fallbackMessage)
 		}
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(response)
-		return
 	}
 
-	// Start the audio-host process
-	process, err := audio.StartAudioHostProcess(config)
-	if err != nil {
-		log.Printf("❌ Failed to start audio-host: %v", err)
+	if process == nil {
 		response := audio.StartAudioResponse{
 			Success: false,
-			Message: fmt.Sprintf("Failed to start audio-host: %v", err),
+			Message: message,
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(response)
 		return
 	}
 
-	// Store the process globally
-	audio.Mutex.Lock()
-	audio.Process = process
-	audio.Mutex.Unlock()
-
-	// Update the reconfiguration system with the current configuration
-	audio.Reconfig.SetCurrentConfig(config)
-	audio.Reconfig.SetRunning(true)
+	rememberAudioConfig(config)
 
 	response := audio.StartAudioResponse{
 		Success: true,
 		Message: "Audio-host process started successfully with bidirectional communication",
 		PID:     process.GetPID(),
 	}
+	if fellBack {
+		response.Message = "Requested configuration failed; audio-host started with safe-mode defaults"
+		response.FellBack = true
+		response.FallbackReason = message
+		response.Config = &config
+	}
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -424,12 +1171,18 @@ func handleStopAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audio.Mutex.Lock()
-	process := audio.Process
-	audio.Process = nil
-	audio.Mutex.Unlock()
+	if !beginLifecycle(w, audio.StateStopping) {
+		return
+	}
+	defer audio.Lifecycle.End()
 
-	if process == nil || !process.IsRunning() {
+	audio.Mutex.RLock()
+	running := audio.Process != nil && audio.Process.IsRunning()
+	audio.Mutex.RUnlock()
+
+	if !running {
+		// Clear out an exited process so the engine state agrees it's stopped
+		audio.Reconfig.Stop()
 		response := map[string]interface{}{
 			"success": false,
 			"message": "No audio-host process is running",
@@ -439,9 +1192,8 @@ func handleStopAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Stop the process
-	err := process.Stop()
-	if err != nil {
+	// Stop the process through the reconfiguration manager so it tracks the state
+	if _, err := audio.Reconfig.Stop(); err != nil {
 		response := map[string]interface{}{
 			"success": false,
 			"message": fmt.Sprintf("Failed to stop audio-host process: %v", err),
@@ -455,98 +1207,342 @@ func handleStopAudio(w http.ResponseWriter, r *http.Request) {
 		"success": true,
 		"message": "Audio-host process stopped successfully",
 	}
-
-	// Update the reconfiguration system to reflect stopped state
-	audio.Reconfig.SetRunning(false)
 
 	json.NewEncoder(w).Encode(response)
 }
 
-func handleAudioCommand(w http.ResponseWriter, r *http.Request) {
+func handleAudioCommand(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request audio.AudioCommandRequest
+	if !decodeJSONBody(w, r, &request, maxCommandBodyBytes) {
+		return
+	}
+
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	if process == nil || !process.IsRunning() {
+		response := audio.AudioCommandResponse{
+			Success: false,
+			Error:   "No audio-host process is running",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	slog.Debug("sending command to audio-host", "command", request.Command)
+
+	// Send command to audio-host
+	output, err := process.SendCommand(request.Command)
+	if err != nil {
+		slog.Error("audio-host command failed", "command", request.Command, "err", err)
+		response := audio.AudioCommandResponse{
+			Success: false,
+			Error:   fmt.Sprintf("Command failed: %v", err),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	slog.Debug("audio-host command response", "command", request.Command, "output", output)
+
+	response := audio.AudioCommandResponse{
+		Success: true,
+		Output:  output,
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func handleAudioStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(audioStatus(true))
+}
+
+// audioStatus reports the audio-host process and engine state. poll also
+// reports xrunDelta and advances its baseline, which only the status endpoint
+// does so the dropouts it shows aren't swallowed by other readers.
+func audioStatus(poll bool) map[string]interface{} {
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	status := map[string]interface{}{
+		"processRunning": false,
+		"engineRunning":  false,
+		"pid":            nil,
+		"lifecycle":      audio.Lifecycle.State().String(),
+	}
+
+	if process != nil && process.IsRunning() {
+		status["processRunning"] = true
+		status["pid"] = process.GetPID()
+		status["requestedConfig"] = process.RequestedConfig()
+		status["actualConfig"] = process.ActualConfig()
+
+		// Try to get detailed status from audio-host
+		query := process.Status
+		if poll {
+			query = process.PollStatus
+		}
+		hostStatus, err := query()
+		if err == nil {
+			status["details"] = hostStatus.Raw
+			status["engineRunning"] = hostStatus.Running
+			status["dspLoad"] = hostStatus.DSPLoad
+			status["xrunCount"] = hostStatus.XRunCount
+			if poll {
+				status["xrunDelta"] = hostStatus.XRunDelta
+			}
+			if warnings := hostStatus.Warnings(); len(warnings) > 0 {
+				status["warnings"] = warnings
+			}
+		}
+	}
+
+	return status
+}
+
+// BootstrapConfig holds the running and saved audio configurations
+type BootstrapConfig struct {
+	Current *audio.AudioConfig      `json:"current"`
+	Saved   *audio.SavedAudioConfig `json:"saved"`
+}
+
+// BootstrapResponse bundles everything the frontend needs at startup
+type BootstrapResponse struct {
+	Devices audio.DevicesData      `json:"devices"`
+	Plugins []audio.PluginSummary  `json:"plugins"`
+	Config  BootstrapConfig        `json:"config"`
+	Status  map[string]interface{} `json:"status"`
+}
+
+func handleBootstrap(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	// Snapshot devices and plugins together so they come from the same load
+	audio.Mutex.RLock()
+	response := BootstrapResponse{
+		Devices: audio.Data.Devices,
+		Plugins: audio.SummarizePlugins(audio.Data.Plugins),
+	}
+	audio.Mutex.RUnlock()
+
+	if audio.Reconfig != nil {
+		response.Config.Current = audio.Reconfig.GetCurrentConfig()
+	}
+	saved, err := audio.LoadLastConfig()
+	if err != nil {
+		slog.Warn("failed to load saved audio configuration", "err", err)
+	}
+	response.Config.Saved = saved
+	response.Status = audioStatus(false)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode bootstrap data", http.StatusInternalServerError)
+		return
+	}
+}
+
+func handleInputLevel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	if process == nil || !process.IsRunning() {
+		response := map[string]interface{}{
+			"success": false,
+			"error":   "No audio-host process is running",
+		}
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	level, err := process.InputLevel()
+	if err != nil {
+		slog.Warn("failed to read input level", "err", err)
+		response := map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to read input level: %v", err),
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	json.NewEncoder(w).Encode(level)
+}
+
+// handleCommandLog returns the recent audio-host command/response pairs.
+// Entries are only recorded when the server runs with --command-log-size.
+func handleCommandLog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	audio.Mutex.RLock()
+	process := audio.Process
+	audio.Mutex.RUnlock()
+
+	entries := []audio.CommandLogEntry{}
+	response := map[string]interface{}{
+		"enabled": audio.CommandLogSize > 0,
+		"pid":     nil,
+	}
+	if process != nil {
+		response["pid"] = process.GetPID()
+		if logged := process.CommandLog(); logged != nil {
+			entries = logged
+		}
+	}
+	response["entries"] = entries
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// handleAudioCapabilities reports the commands the running audio-host accepts,
+// so clients can hide features an older build lacks
+func handleAudioCapabilities(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-	if r.Method != "POST" {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	var request audio.AudioCommandRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
-		return
-	}
-
 	audio.Mutex.RLock()
 	process := audio.Process
 	audio.Mutex.RUnlock()
 
 	if process == nil || !process.IsRunning() {
-		response := audio.AudioCommandResponse{
-			Success: false,
-			Error:   "No audio-host process is running",
-		}
 		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(response)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "No audio-host process is running",
+		})
 		return
 	}
 
-	log.Printf("🎯 Sending command to audio-host: %s", request.Command)
+	commands, known := process.Commands()
+	if commands == nil {
+		commands = []string{}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pid":      process.GetPID(),
+		"known":    known,
+		"commands": commands,
+	})
+}
 
-	// Send command to audio-host
-	output, err := process.SendCommand(request.Command)
+// isLoopbackRequest reports whether r came from the local machine
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		log.Printf("❌ Command failed: %v", err)
-		response := audio.AudioCommandResponse{
-			Success: false,
-			Error:   fmt.Sprintf("Command failed: %v", err),
-		}
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(response)
-		return
+		host = r.RemoteAddr
 	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
 
-	log.Printf("✅ Command response: %s", output)
-
-	response := audio.AudioCommandResponse{
-		Success: true,
-		Output:  output,
+// isSameOriginRequest reports whether r either carries no Origin header
+// (curl, scripts) or one naming this server. Browsers attach Origin to every
+// cross-site POST, so this keeps other pages from driving local endpoints.
+func isSameOriginRequest(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
 	}
-
-	json.NewEncoder(w).Encode(response)
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return u.Host == r.Host
 }
 
-func handleAudioStatus(w http.ResponseWriter, r *http.Request) {
+// handleFlushCaches drops every memoized result (audio-host binary check,
+// legacy audio-host command lists, idempotent responses, input level) and re-enumerates devices and plugins,
+// so operators can pick up changes without restarting. Only local clients
+// may call it, and browsers only from the server's own origin.
+func handleFlushCaches(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 
+	if !isLoopbackRequest(r) {
+		slog.Warn("rejected cache flush from remote client", "remoteAddr", r.RemoteAddr)
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "Cache flush is only allowed from localhost",
+		})
+		return
+	}
+
+	if !isSameOriginRequest(r) {
+		slog.Warn("rejected cross-origin cache flush", "origin", r.Header.Get("Origin"))
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   "Cache flush is not allowed from other origins",
+		})
+		return
+	}
+
+	audio.ResetHostCheck()
+	audio.ResetHostCommands()
+
+	idempotencyMu.Lock()
+	for key, entry := range idempotencyCache {
+		// Leave in-flight requests alone; their waiters hold the entry
+		if entry.status != 0 {
+			delete(idempotencyCache, key)
+		}
+	}
+	idempotencyMu.Unlock()
+
 	audio.Mutex.RLock()
 	process := audio.Process
 	audio.Mutex.RUnlock()
+	if process != nil {
+		process.ResetInputLevel()
+	}
 
-	status := map[string]interface{}{
-		"processRunning": false,
-		"engineRunning":  false,
-		"pid":            nil,
+	if err := refreshServerData(r.Context()); err != nil {
+		slog.Error("re-enumeration after cache flush failed", "err", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Caches flushed but re-enumeration failed: %v", err),
+		})
+		return
 	}
 
-	if process != nil && process.IsRunning() {
-		status["processRunning"] = true
-		status["pid"] = process.GetPID()
+	slog.Info("caches flushed")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"flushed": []string{"audioHostCheck", "idempotency", "inputLevel", "devices", "plugins"},
+	})
+}
 
-		// Try to get detailed status from audio-host
-		output, err := process.SendCommand("status")
-		if err == nil {
-			status["details"] = output
+func handleChangeCapabilities(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
 
-			// Parse engine running state from audio-host status
-			if strings.Contains(output, "running=true") {
-				status["engineRunning"] = true
-			}
-		}
+	if err := json.NewEncoder(w).Encode(audio.Reconfig.ChangeCapabilities()); err != nil {
+		http.Error(w, "Failed to encode change capabilities", http.StatusInternalServerError)
+		return
 	}
-
-	json.NewEncoder(w).Encode(status)
 }
 
 func handleSuggestSampleRate(w http.ResponseWriter, r *http.Request) {
@@ -589,9 +1585,10 @@ func handleSuggestSampleRate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := map[string]interface{}{
-		"success":    true,
-		"sampleRate": sampleRate,
-		"message":    fmt.Sprintf("Recommended sample rate: %d Hz", sampleRate),
+		"success":       true,
+		"sampleRate":    sampleRate,
+		"message":       fmt.Sprintf("Recommended sample rate: %d Hz", sampleRate),
+		"compatibility": checkDeviceCompatibility(inputDeviceID, outputDeviceID),
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -607,8 +1604,7 @@ func handleTestDevices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request audio.DeviceTestRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSONBody(w, r, &request, maxConfigBodyBytes) {
 		return
 	}
 
@@ -619,17 +1615,15 @@ func handleTestDevices(w http.ResponseWriter, r *http.Request) {
 		BufferSize:         request.BufferSize,
 	}
 
-	// Set default buffer size if not specified
-	if config.BufferSize == 0 {
-		config.BufferSize = 256
-	}
+	// Fill unset sample rate and buffer size from the configured defaults
+	config = config.WithDefaults()
 
 	// Use default output device if not specified
 	if request.OutputDeviceID != 0 {
 		// Note: Current audio-host doesn't support output device selection
 		// but we can validate it exists
 		found := false
-		for _, device := range audio.Data.Devices.AudioOutput {
+		for _, device := range audio.Devices().AudioOutput {
 			if device.DeviceID == request.OutputDeviceID {
 				found = true
 				break
@@ -648,8 +1642,8 @@ func handleTestDevices(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	log.Printf("🧪 Testing device configuration: input %d, sample rate %.0f Hz, buffer %d",
-		config.AudioInputDeviceID, config.SampleRate, config.BufferSize)
+	slog.Info("testing device configuration",
+		"inputDevice", config.AudioInputDeviceID, "sampleRate", config.SampleRate, "bufferSize", config.BufferSize)
 
 	// Test the configuration
 	isReady, errorMsg, action := testDeviceConfiguration(config)
@@ -662,9 +1656,9 @@ func handleTestDevices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if isReady {
-		log.Printf("✅ Device test successful - audio ready")
+		slog.Info("device test successful")
 	} else {
-		log.Printf("❌ Device test failed: %s", errorMsg)
+		slog.Warn("device test failed", "err", errorMsg)
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -679,9 +1673,13 @@ func handleSwitchDevices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !beginLifecycle(w, audio.StateSwitching) {
+		return
+	}
+	defer audio.Lifecycle.End()
+
 	var request audio.DeviceSwitchRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSONBody(w, r, &request, maxConfigBodyBytes) {
 		return
 	}
 
@@ -694,17 +1692,15 @@ func handleSwitchDevices(w http.ResponseWriter, r *http.Request) {
 		EnableTestTone:     false, // Default to no test tone when switching devices
 	}
 
-	// Set default buffer size if not specified
-	if config.BufferSize == 0 {
-		config.BufferSize = 256
-	}
+	// Fill unset sample rate and buffer size from the configured defaults
+	config = config.WithDefaults()
 
 	// Validate output device if specified
 	if request.OutputDeviceID != 0 {
 		// Note: Current audio-host doesn't support output device selection
 		// but we can validate it exists for future compatibility
 		found := false
-		for _, device := range audio.Data.Devices.AudioOutput {
+		for _, device := range audio.Devices().AudioOutput {
 			if device.DeviceID == request.OutputDeviceID {
 				found = true
 				break
@@ -723,42 +1719,33 @@ func handleSwitchDevices(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	log.Printf("🔄 Switching to device configuration: input %d, sample rate %.0f Hz, buffer %d",
-		config.AudioInputDeviceID, config.SampleRate, config.BufferSize)
+	slog.Info("switching device configuration",
+		"inputDevice", config.AudioInputDeviceID, "sampleRate", config.SampleRate, "bufferSize", config.BufferSize)
 
 	// Switch the devices
-	isReady, errorMsg, action, wasRunning, pid := switchAudioDevices(config)
-
-	response := audio.DeviceSwitchResponse{
-		IsAudioReady:           isReady,
-		ErrorMessage:           errorMsg,
-		RequiredAction:         action,
-		NewConfig:              config,
-		PreviousProcessRunning: wasRunning,
-		ProcessRestarted:       wasRunning && isReady, // Only true if something was running and switch succeeded
-		PID:                    pid,
-	}
+	response := switchAudioDevices(config)
 
-	if isReady {
-		if wasRunning {
-			log.Printf("✅ Device switch successful - audio-host restarted with PID %d", pid)
-		} else {
-			log.Printf("✅ Device switch successful - audio-host started with PID %d", pid)
-		}
+	if response.IsAudioReady {
+		slog.Info("device switch successful", "pid", response.PID, "restarted", response.ProcessRestarted)
 	} else {
-		log.Printf("❌ Device switch failed: %s", errorMsg)
-		if !isReady {
-			// If switch failed, make sure we're in a clean state
-			audio.Mutex.Lock()
-			audio.Process = nil
-			audio.Mutex.Unlock()
-			audio.Reconfig.SetRunning(false)
-		}
+		slog.Warn("device switch failed", "err", response.ErrorMessage)
 	}
 
 	json.NewEncoder(w).Encode(response)
 }
 
+// AudioDevice must keep satisfying the dashboard's Device interface
+var _ debug.Device = audio.AudioDevice{}
+
+// toDebugDevices converts audio devices for the debug dashboard
+func toDebugDevices(devices []audio.AudioDevice) []debug.Device {
+	result := make([]debug.Device, len(devices))
+	for i, device := range devices {
+		result[i] = device
+	}
+	return result
+}
+
 func handleDebug(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 
@@ -767,36 +1754,26 @@ func handleDebug(w http.ResponseWriter, r *http.Request) {
 	process := audio.Process
 	audio.Mutex.RUnlock()
 
-	// Convert AudioDevice slices to debug.Device slices
-	inputDevices := make([]debug.Device, len(audio.Data.Devices.AudioInput))
-	for i, device := range audio.Data.Devices.AudioInput {
-		inputDevices[i] = device
-	}
-
-	outputDevices := make([]debug.Device, len(audio.Data.Devices.AudioOutput))
-	for i, device := range audio.Data.Devices.AudioOutput {
-		outputDevices[i] = device
-	}
-
 	// Prepare data for the debug dashboard
+	devices := audio.Devices()
 	data := debug.DashboardData{
 		ProcessRunning: process != nil && process.IsRunning(),
-		InputDevices:   inputDevices,
-		OutputDevices:  outputDevices,
-		PluginCount:    len(audio.Data.Plugins),
-		DefaultInput:   audio.Data.Devices.Defaults.DefaultInput,
-		DefaultOutput:  audio.Data.Devices.Defaults.DefaultOutput,
-		DefaultRate:    audio.Data.Devices.DefaultSampleRate,
-		Timestamp:      audio.Data.Devices.Timestamp,
+		InputDevices:   toDebugDevices(devices.AudioInput),
+		OutputDevices:  toDebugDevices(devices.AudioOutput),
+		PluginCount:    len(audio.Plugins()),
+		DefaultInput:   devices.Defaults.DefaultInput,
+		DefaultOutput:  devices.Defaults.DefaultOutput,
+		DefaultRate:    devices.DefaultSampleRate,
+		Timestamp:      devices.Timestamp,
 	}
 
 	if data.ProcessRunning {
 		data.PID = process.GetPID()
 		// Try to get engine status
-		output, err := process.SendCommand("status")
+		hostStatus, err := process.Status()
 		if err == nil {
-			data.StatusDetails = output
-			data.EngineRunning = strings.Contains(output, "running=true")
+			data.StatusDetails = hostStatus.Raw
+			data.EngineRunning = hostStatus.Running
 		} else {
 			data.StatusDetails = fmt.Sprintf("Error getting status: %v", err)
 		}
@@ -817,9 +1794,13 @@ func handleConfigChange(w http.ResponseWriter, r *http.Request, audioReconfig *a
 		return
 	}
 
+	if !beginLifecycle(w, audio.StateSwitching) {
+		return
+	}
+	defer audio.Lifecycle.End()
+
 	var request ConfigChangeRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+	if !decodeJSONBody(w, r, &request, maxConfigBodyBytes) {
 		return
 	}
 
@@ -828,7 +1809,7 @@ func handleConfigChange(w http.ResponseWriter, r *http.Request, audioReconfig *a
 		request.Reason = "Configuration change requested"
 	}
 
-	log.Printf("🎯 Config change request: %s", request.Reason)
+	slog.Info("config change request", "reason", request.Reason)
 
 	// Validate the new configuration first
 	if err := validateAudioConfig(request.Config); err != nil {
@@ -886,6 +1867,11 @@ func handleConfigChange(w http.ResponseWriter, r *http.Request, audioReconfig *a
 
 // validateAudioConfig performs comprehensive validation of audio configuration
 func validateAudioConfig(config audio.AudioConfig) error {
+	// Reject zero, negative, NaN and absurd rates before any device checks
+	if err := audio.ValidateSampleRateValue(config.SampleRate); err != nil {
+		return err
+	}
+
 	// Buffer size validation
 	if config.BufferSize != 0 && (config.BufferSize < 32 || config.BufferSize > 1024) {
 		return fmt.Errorf("invalid buffer size: %d (must be 32-1024 samples)", config.BufferSize)
@@ -899,6 +1885,119 @@ func validateAudioConfig(config audio.AudioConfig) error {
 	return nil
 }
 
+// LogLevelRequest represents a request to change the server log level
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// handleLogLevel reports the active log level (GET) or changes it live (PUT)
+func handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == "PUT" {
+		var request LogLevelRequest
+		if !decodeJSONBody(w, r, &request, maxCommandBodyBytes) {
+			return
+		}
+
+		if err := logging.SetLevel(request.Level); err != nil {
+			response := map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		slog.Info("log level changed", "level", logging.Level())
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"level":   logging.Level(),
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func handleMIDISend(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	var request audio.MIDISendRequest
+	if !decodeJSONBody(w, r, &request, maxCommandBodyBytes) {
+		return
+	}
+
+	status := http.StatusOK
+	err := request.Validate()
+	if err != nil {
+		status = http.StatusBadRequest
+	} else if _, found := audio.FindMIDIOutput(audio.Devices(), request.DeviceUID); !found {
+		status = http.StatusNotFound
+		err = fmt.Errorf("MIDI output device %s not found", request.DeviceUID)
+	} else if err = audio.SendMIDI(request); errors.Is(err, audio.ErrNotSupported) {
+		status = http.StatusNotImplemented
+	} else if err != nil {
+		status = http.StatusInternalServerError
+	}
+
+	if err != nil {
+		slog.Warn("MIDI send failed", "device", request.DeviceUID, "err", err)
+		response := map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func handleMIDIClock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	uid := r.PathValue("uid")
+	device, found := audio.FindMIDIInput(audio.Devices(), uid)
+	if !found {
+		http.Error(w, fmt.Sprintf("MIDI input device %s not found", uid), http.StatusNotFound)
+		return
+	}
+
+	window := audio.DefaultMIDIClockWindow
+	if value := r.URL.Query().Get("window"); value != "" {
+		ms, err := strconv.Atoi(value)
+		if err != nil || ms <= 0 || ms > 5000 {
+			http.Error(w, "Invalid window (must be 1-5000 ms)", http.StatusBadRequest)
+			return
+		}
+		window = time.Duration(ms) * time.Millisecond
+	}
+
+	status, err := audio.ProbeMIDIClock(device, window)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, audio.ErrNotSupported) {
+			code = http.StatusNotImplemented
+		}
+		response := map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	json.NewEncoder(w).Encode(status)
+}
+
 // changeTypeToString converts audio.ChangeRequirement enum to string
 func changeTypeToString(changeType audio.ChangeRequirement) string {
 	switch changeType {
@@ -920,7 +2019,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key")
 
 		// Handle preflight requests
 		if r.Method == "OPTIONS" {
@@ -932,33 +2031,243 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// gzipMinSize is the smallest response body worth compressing
+const gzipMinSize = 1024
+
+// gzipResponseWriter buffers the start of a response until it knows whether
+// the body is large enough and of a compressible type, then either streams
+// it through gzip or passes it through untouched
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz      *gzip.Writer
+	buf     []byte
+	status  int
+	decided bool
+}
+
+// shouldCompress reports whether the response content type is eligible for gzip.
+// Event streams are never compressed since they must stay unbuffered.
+func (g *gzipResponseWriter) shouldCompress() bool {
+	header := g.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	return strings.HasPrefix(contentType, "application/json")
+}
+
+// decide commits to compressed or plain output and writes any buffered data
+func (g *gzipResponseWriter) decide(compress bool) error {
+	g.decided = true
+	if compress {
+		g.Header().Del("Content-Length")
+		g.Header().Set("Content-Encoding", "gzip")
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+	}
+	if g.status != 0 {
+		g.ResponseWriter.WriteHeader(g.status)
+	}
+
+	buffered := g.buf
+	g.buf = nil
+	if len(buffered) == 0 {
+		return nil
+	}
+	if g.gz != nil {
+		_, err := g.gz.Write(buffered)
+		return err
+	}
+	_, err := g.ResponseWriter.Write(buffered)
+	return err
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.decided || g.status != 0 {
+		return
+	}
+	g.status = status
+	// Event streams and bodiless responses go out immediately
+	if !g.shouldCompress() || status == http.StatusNoContent || status == http.StatusNotModified {
+		g.decide(false)
+	}
+}
+
+func (g *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !g.decided && !g.shouldCompress() {
+		if err := g.decide(false); err != nil {
+			return 0, err
+		}
+	}
+	if !g.decided {
+		g.buf = append(g.buf, data...)
+		if len(g.buf) < gzipMinSize {
+			return len(data), nil
+		}
+		if err := g.decide(g.shouldCompress()); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if g.gz != nil {
+		return g.gz.Write(data)
+	}
+	return g.ResponseWriter.Write(data)
+}
+
+// Flush sends buffered data to the client, committing to compression only
+// if enough data has already been written
+func (g *gzipResponseWriter) Flush() {
+	if !g.decided {
+		g.decide(len(g.buf) >= gzipMinSize && g.shouldCompress())
+	}
+	if g.gz != nil {
+		g.gz.Flush()
+	}
+	if flusher, ok := g.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// close finishes the response, writing small bodies uncompressed
+func (g *gzipResponseWriter) close() {
+	if !g.decided {
+		g.decide(false)
+	}
+	if g.gz != nil {
+		g.gz.Close()
+	}
+}
+
+// acceptsGzip reports whether the client advertised gzip support
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// addVary adds field to the Vary header unless it is already listed
+func addVary(header http.Header, field string) {
+	for _, value := range header.Values("Vary") {
+		for _, existing := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(existing), field) {
+				return
+			}
+		}
+	}
+	header.Add("Vary", field)
+}
+
+// gzipMiddleware compresses JSON responses above gzipMinSize for clients that accept gzip
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addVary(w.Header(), "Accept-Encoding")
+
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// apiVersionPrefix is where every API route is served. The unversioned /api
+// paths remain as deprecated aliases for existing clients.
+const apiVersionPrefix = "/api/v1"
+
+// versionedPath maps an unversioned /api path to its /api/v1 successor
+func versionedPath(path string) string {
+	return apiVersionPrefix + strings.TrimPrefix(path, "/api")
+}
+
+// unversionedPath maps a /api/v1 path back to its unversioned form
+func unversionedPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, apiVersionPrefix+"/"); ok {
+		return "/api/" + rest
+	}
+	return path
+}
+
+// handleAPI registers handler for a "METHOD /api/..." pattern under both the
+// versioned prefix and the legacy unversioned path
+func handleAPI(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	mux.HandleFunc(method+" "+versionedPath(path), handler)
+	mux.HandleFunc(pattern, deprecatedAlias(handler))
+}
+
+// deprecatedAlias marks responses on unversioned paths with a Deprecation
+// header and a Link to the versioned successor
+func deprecatedAlias(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		successor := versionedPath(r.URL.Path)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+		slog.Debug("deprecated API path used", "path", r.URL.Path, "successor", successor)
+		next(w, r)
+	}
+}
+
 func setupRoutes() *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// API routes
-	mux.HandleFunc("GET /api/health", handleHealth)
-	mux.HandleFunc("GET /api/devices", handleDevices)
-	mux.HandleFunc("GET /api/plugins", handlePlugins)
-	mux.HandleFunc("GET /api/plugins/{id}", handlePlugin)
-	mux.HandleFunc("GET /api/data", handleServerData)
+	handleAPI(mux, "GET /api/health", handleHealth)
+	handleAPI(mux, "GET /api/ready", handleReady)
+	handleAPI(mux, "GET /api/devices", handleDevices)
+	handleAPI(mux, "GET /api/devices/counts", handleDeviceCounts)
+	handleAPI(mux, "GET /api/devices/{uid}/capabilities", handleDeviceCapabilities)
+	handleAPI(mux, "GET /api/plugins", handlePlugins)
+	handleAPI(mux, "GET /api/plugins/{id}", handlePlugin)
+	handleAPI(mux, "GET /api/plugins/{id}/parameters", handlePluginParameters)
+	handleAPI(mux, "GET /api/data", handleServerData)
+	handleAPI(mux, "GET /api/bootstrap", handleBootstrap)
 
 	// Audio control routes
-	mux.HandleFunc("POST /api/audio/start", handleStartAudio)
-	mux.HandleFunc("POST /api/audio/stop", handleStopAudio)
-	mux.HandleFunc("POST /api/audio/command", handleAudioCommand)
-	mux.HandleFunc("GET /api/audio/status", handleAudioStatus)
-	mux.HandleFunc("GET /api/audio/suggest-sample-rate", handleSuggestSampleRate)
-	mux.HandleFunc("POST /api/audio/config-change", func(w http.ResponseWriter, r *http.Request) {
+	handleAPI(mux, "POST /api/audio/start", idempotent(handleStartAudio))
+	handleAPI(mux, "POST /api/audio/stop", idempotent(handleStopAudio))
+	handleAPI(mux, "POST /api/audio/command", handleAudioCommand)
+	handleAPI(mux, "GET /api/audio/status", handleAudioStatus)
+	handleAPI(mux, "GET /api/audio/input-level", handleInputLevel)
+	handleAPI(mux, "GET /api/audio/capabilities", handleAudioCapabilities)
+	handleAPI(mux, "GET /api/audio/change-capabilities", handleChangeCapabilities)
+	handleAPI(mux, "GET /api/audio/command-log", handleCommandLog)
+	handleAPI(mux, "GET /api/audio/suggest-sample-rate", handleSuggestSampleRate)
+	handleAPI(mux, "POST /api/audio/config-change", func(w http.ResponseWriter, r *http.Request) {
 		handleConfigChange(w, r, audio.Reconfig)
 	})
-	mux.HandleFunc("POST /api/audio/test-devices", handleTestDevices)
-	mux.HandleFunc("POST /api/audio/switch-devices", handleSwitchDevices)
+	handleAPI(mux, "POST /api/audio/test-devices", handleTestDevices)
+	handleAPI(mux, "POST /api/audio/switch-devices", handleSwitchDevices)
+
+	// MIDI routes
+	handleAPI(mux, "POST /api/midi/send", handleMIDISend)
+	handleAPI(mux, "GET /api/midi/{uid}/clock", handleMIDIClock)
+
+	// Server administration routes
+	handleAPI(mux, "GET /api/server/log-level", handleLogLevel)
+	handleAPI(mux, "PUT /api/server/log-level", handleLogLevel)
+	handleAPI(mux, "POST /api/admin/flush-caches", handleFlushCaches)
 
 	// Debug/testing routes
 	mux.HandleFunc("GET /debug", handleDebug)
 
 	// Static file serving (for WASM app) with no-cache headers for development
-	fs := http.FileServer(http.Dir("./frontend/static/"))
+	fs := http.FileServer(staticFileSystem())
 
 	// Wrap the file server to add no-cache headers
 	noCacheFS := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -988,68 +2297,149 @@ func checkPortAvailable(port string) error {
 	return nil
 }
 
-func main() {
-	log.Println("🚀 Starting Rackless Audio Server...")
+// apiEndpoints lists the routes announced in the startup log
+var apiEndpoints = []struct {
+	Route       string
+	Description string
+}{
+	{"GET /api/health", "Server health status"},
+	{"GET /api/ready", "Readiness: audio-host binary runnable and devices loaded"},
+	{"GET /api/devices", "Audio device information"},
+	{"GET /api/devices/counts", "Number of devices per category and the defaults"},
+	{"GET /api/devices/{uid}/capabilities", "Consolidated capabilities for one device"},
+	{"GET /api/plugins", "AudioUnit plugin list"},
+	{"GET /api/plugins/{id}", "Individual plugin details (?summary=true omits parameters)"},
+	{"GET /api/plugins/{id}/parameters", "Paginated plugin parameters (?offset=&limit=)"},
+	{"GET /api/data", "Complete server data (?refresh=true re-enumerates first, ?timeout= bounds it)"},
+	{"GET /api/bootstrap", "Devices, plugin summaries, audio config and status in one call"},
+	{"POST /api/audio/start", "Start audio-host with validation (honours Idempotency-Key)"},
+	{"POST /api/audio/stop", "Stop audio-host (honours Idempotency-Key)"},
+	{"POST /api/audio/command", "Send command to running audio-host"},
+	{"GET /api/audio/status", "Get audio-host status"},
+	{"GET /api/audio/input-level", "Current input peak/RMS level in dBFS"},
+	{"GET /api/audio/capabilities", "Commands the running audio-host accepts"},
+	{"GET /api/audio/change-capabilities", "Which config changes are safe while audio runs"},
+	{"GET /api/audio/command-log", "Recent audio-host commands and responses (with --command-log-size)"},
+	{"GET /api/audio/suggest-sample-rate", "Find compatible sample rate"},
+	{"POST /api/audio/test-devices", "Test device configuration (returns isAudioReady)"},
+	{"POST /api/audio/switch-devices", "Switch audio devices (stops current, starts new)"},
+	{"POST /api/midi/send", "Send a CC or program change to a MIDI output"},
+	{"GET /api/midi/{uid}/clock", "Probe a MIDI input for clock and transport (opens the port)"},
+	{"GET|PUT /api/server/log-level", "Get or change the log level at runtime"},
+	{"POST /api/admin/flush-caches", "Drop memoized results and re-enumerate devices and plugins (localhost only)"},
+	{"GET /debug", "Debug dashboard (HTML interface)"},
+	{"GET /", "Static file serving (web app)"},
+}
 
-	// Initialize the audio package
-	if err := audio.Initialize(); err != nil {
-		log.Fatalf("❌ Failed to initialize audio package: %v", err)
-	}
+// fatal logs an error and exits, replacing log.Fatalf for structured logging
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func main() {
+	dataDir := flag.String("data-dir", audio.DefaultDataDir(),
+		"Directory containing standalone tools, frontend assets and data files (env "+audio.DataDirEnv+")")
+	flag.BoolVar(&preferDiskAssets, "dev", false, "Serve frontend assets from disk instead of the embedded copy")
+	flag.BoolVar(&strictJSON, "strict-json", envBool("RACKLESS_STRICT_JSON", false),
+		"Reject request bodies containing unknown fields (env RACKLESS_STRICT_JSON)")
+	flag.IntVar(&audio.DefaultBufferSize, "default-buffer-size", audio.DefaultBufferSize,
+		"Buffer size used when a request doesn't specify one")
+	flag.Float64Var(&audio.DefaultSampleRate, "default-sample-rate", 0,
+		"Sample rate used when a request doesn't specify one (0 uses the default output device's rate)")
+	flag.StringVar(&audio.AudioHostPath, "audio-host", "",
+		"Path to the audio-host binary (env "+audio.AudioHostPathEnv+" takes precedence)")
+	flag.StringVar(&audio.AudioHostDir, "audio-host-dir", "",
+		"Working directory for audio-host (default: the server's)")
+	flag.Func("audio-host-env", "Extra KEY=VALUE environment variable for audio-host (repeatable)", func(value string) error {
+		if key, _, ok := strings.Cut(value, "="); !ok || key == "" {
+			return fmt.Errorf("expected KEY=VALUE, got %q", value)
+		}
+		audio.AudioHostEnv = append(audio.AudioHostEnv, value)
+		return nil
+	})
+	flag.DurationVar(&audio.ToolTimeout, "tool-timeout", audio.DefaultToolTimeout,
+		"Maximum run time for the devices and inspector tools")
+	flag.DurationVar(&audio.Timeouts.Ready, "ready-timeout", audio.DefaultAudioTimeouts.Ready,
+		"How long to wait for audio-host to initialize its devices")
+	flag.DurationVar(&audio.Timeouts.Command, "command-timeout", audio.DefaultAudioTimeouts.Command,
+		"How long to wait for audio-host to answer a command")
+	flag.DurationVar(&audio.Timeouts.Stop, "stop-timeout", audio.DefaultAudioTimeouts.Stop,
+		"How long audio-host may take to exit before it is killed")
+	flag.StringVar(&audio.ReadySentinel, "ready-sentinel", audio.DefaultReadySentinel,
+		"Startup marker audio-host prints on stderr when it is ready")
+	flag.IntVar(&audio.CommandLogSize, "command-log-size", 0,
+		"Number of audio-host commands kept for GET /api/audio/command-log (0 disables)")
+	autoStart := flag.Bool("autostart", envBool("RACKLESS_AUTOSTART", true),
+		"Resume audio with the last started configuration on boot (env RACKLESS_AUTOSTART)")
+	logLevel := flag.String("log-level", envOrDefault("RACKLESS_LOG_LEVEL", "info"),
+		"Log level: debug, info, warn or error (env RACKLESS_LOG_LEVEL)")
+	logFormat := flag.String("log-format", envOrDefault("RACKLESS_LOG_FORMAT", logging.FormatText),
+		"Log output format: text or json (env RACKLESS_LOG_FORMAT)")
+	flag.Parse()
+
+	if err := logging.Setup(os.Stderr, *logLevel, *logFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(2)
+	}
+
+	slog.Info("starting Rackless audio server", "dataDir", *dataDir, "logLevel", *logLevel)
+	audio.DataDir = *dataDir
 
 	// Check port availability first before doing any expensive operations
 	const serverPort = "8080"
-	log.Printf("🔍 Checking if port %s is available...", serverPort)
 	if err := checkPortAvailable(serverPort); err != nil {
-		log.Fatalf("❌ Server startup failed: %v\n\n💡 Possible solutions:\n   • Stop any other process using port %s\n   • Wait a moment and try again\n   • Check with: lsof -i :%s", err, serverPort, serverPort)
+		fatal("server startup failed: stop any other process using the port or check with lsof -i :"+serverPort,
+			"port", serverPort, "err", err)
 	}
-	log.Printf("✅ Port %s is available", serverPort)
+	slog.Debug("port is available", "port", serverPort)
 
-	// Load device information
-	if err := audio.LoadDevices(); err != nil {
-		log.Fatalf("❌ Failed to load devices: %v", err)
+	if err := initServerData(); err != nil {
+		fatal("failed to initialize audio package", "err", err)
 	}
 
-	// Load plugin information
-	if err := audio.LoadPlugins(); err != nil {
-		log.Fatalf("❌ Failed to load plugins: %v", err)
-	}
+	devices := audio.Devices()
+	slog.Info("Rackless audio server initialized",
+		"defaultInput", devices.Defaults.DefaultInput,
+		"defaultOutput", devices.Defaults.DefaultOutput,
+		"defaultSampleRate", devices.DefaultSampleRate,
+		"plugins", len(audio.Plugins()))
 
-	log.Println("🎵 Rackless Audio Server initialized successfully!")
-	log.Printf("📊 Server data summary:")
-	log.Printf("   • Default audio input: Device %d", audio.Data.Devices.Defaults.DefaultInput)
-	log.Printf("   • Default audio output: Device %d", audio.Data.Devices.Defaults.DefaultOutput)
-	log.Printf("   • Default sample rate: %.0f Hz", audio.Data.Devices.DefaultSampleRate)
-	log.Printf("   • Total plugins available: %d", len(audio.Data.Plugins))
+	autoStartAudio(*autoStart)
 
 	// Setup routes
 	router := setupRoutes()
-	handler := corsMiddleware(router)
-
-	log.Printf("🌐 Starting HTTP server on :%s...", serverPort)
-	log.Println("📡 API endpoints available:")
-	log.Println("   • GET /api/health - Server health status")
-	log.Println("   • GET /api/devices - Audio device information")
-	log.Println("   • GET /api/plugins - AudioUnit plugin list")
-	log.Println("   • GET /api/plugins/{id} - Individual plugin details")
-	log.Println("   • GET /api/data - Complete server data")
-	log.Println("   • POST /api/audio/start - Start audio-host with validation")
-	log.Println("   • POST /api/audio/stop - Stop audio-host")
-	log.Println("   • POST /api/audio/command - Send command to running audio-host")
-	log.Println("   • GET /api/audio/status - Get audio-host status")
-	log.Println("   • GET /api/audio/suggest-sample-rate - Find compatible sample rate")
-	log.Println("   • POST /api/audio/test-devices - Test device configuration (returns isAudioReady)")
-	log.Println("   • POST /api/audio/switch-devices - Switch audio devices (stops current, starts new)")
-	log.Println("   • GET /debug - Debug dashboard (HTML interface)")
-	log.Println("   • GET / - Static file serving (web app)")
-	log.Println("")
-	log.Println("🎯 Smart audio controller ready with bidirectional communication!")
-	log.Println("   • Server validates sample rate compatibility before starting audio-host")
-	log.Println("   • Audio-host provides clear error messages for any failures")
-	log.Println("   • Real-time command communication with running audio-host processes")
-	log.Println("   • Automatic process management and cleanup")
+	handler := corsMiddleware(gzipMiddleware(router))
+
+	for _, endpoint := range apiEndpoints {
+		method, path, _ := strings.Cut(endpoint.Route, " ")
+		if strings.HasPrefix(path, "/api/") {
+			endpoint.Route = method + " " + versionedPath(path)
+		}
+		slog.Info("endpoint available", "route", endpoint.Route, "description", endpoint.Description)
+	}
+	slog.Info("unversioned /api paths remain available but are deprecated", "successor", apiVersionPrefix)
+	slog.Info("starting HTTP server", "addr", ":"+serverPort)
 
 	err := http.ListenAndServe(":"+serverPort, handler)
 	if err != nil {
-		log.Fatalf("❌ Failed to start server: %v", err)
+		fatal("failed to start server", "err", err)
+	}
+}
+
+// envBool parses a boolean environment variable, returning fallback if it is unset or invalid
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// envOrDefault returns the environment variable value or the fallback if unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
 	}
+	return fallback
 }
@@ -2,15 +2,22 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/shaban/rackless/audio"
+	"github.com/shaban/rackless/internal/logging"
 )
 
 // Helper functions for tests
@@ -31,7 +38,8 @@ func stopAudioHost() {
 // initializeAudioForTest ensures audio package is properly initialized for tests
 func initializeAudioForTest(t *testing.T) {
 	if audio.Reconfig == nil {
-		if err := audio.Initialize(); err != nil {
+		audio.Initialize()
+		if err := audio.LoadDevices(); err != nil {
 			t.Fatalf("Failed to initialize audio package for test: %v", err)
 		}
 	}
@@ -571,9 +579,7 @@ func TestBufferSizeEdgeCases(t *testing.T) {
 // The tests below have been updated to reflect audio-host's actual behavior.
 func TestHandleTestDevices(t *testing.T) {
 	// Initialize audio system
-	if err := audio.Initialize(); err != nil {
-		t.Fatalf("Failed to initialize audio: %v", err)
-	}
+	audio.Initialize()
 	if err := audio.LoadDevices(); err != nil {
 		t.Fatalf("Failed to load devices: %v", err)
 	}
@@ -686,9 +692,7 @@ func TestHandleTestDevices(t *testing.T) {
 // TestHandleSwitchDevices tests the seamless device switching that's critical for UX
 func TestHandleSwitchDevices(t *testing.T) {
 	// Initialize audio system
-	if err := audio.Initialize(); err != nil {
-		t.Fatalf("Failed to initialize audio: %v", err)
-	}
+	audio.Initialize()
 	if err := audio.LoadDevices(); err != nil {
 		t.Fatalf("Failed to load devices: %v", err)
 	}
@@ -819,9 +823,7 @@ func TestHandleSwitchDevices(t *testing.T) {
 // TestHandleConfigChange tests the intelligent configuration change system
 func TestHandleConfigChange(t *testing.T) {
 	// Initialize audio system
-	if err := audio.Initialize(); err != nil {
-		t.Fatalf("Failed to initialize audio: %v", err)
-	}
+	audio.Initialize()
 	if err := audio.LoadDevices(); err != nil {
 		t.Fatalf("Failed to load devices: %v", err)
 	}
@@ -930,3 +932,1323 @@ func TestHandleConfigChange(t *testing.T) {
 		}
 	})
 }
+
+// =============================================================================
+// RESPONSE ENCODING BENCHMARKS
+// =============================================================================
+
+// makeSyntheticPlugins builds a large plugin set for encoding benchmarks
+func makeSyntheticPlugins(pluginCount, paramCount int) []audio.Plugin {
+	plugins := make([]audio.Plugin, pluginCount)
+	for i := range plugins {
+		params := make([]audio.PluginParameter, paramCount)
+		for j := range params {
+			params[j] = audio.PluginParameter{
+				DisplayName:  fmt.Sprintf("Parameter %d", j),
+				DefaultValue: 0.5,
+				CurrentValue: 0.5,
+				Address:      j,
+				MaxValue:     1,
+				Unit:         "Generic",
+				Identifier:   fmt.Sprintf("%d", j),
+				IsWritable:   true,
+				RawFlags:     3233808384,
+			}
+		}
+		plugins[i] = audio.Plugin{
+			Parameters:     params,
+			ManufacturerID: "appl",
+			Name:           fmt.Sprintf("Synthetic Plugin %d", i),
+			Type:           "aufx",
+			Subtype:        fmt.Sprintf("sy%02d", i%100),
+		}
+	}
+	return plugins
+}
+
+// BenchmarkHandlePluginsStreaming measures the streaming encoder used by handlePlugins
+func BenchmarkHandlePluginsStreaming(b *testing.B) {
+	original := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(500, 100)
+	defer func() { audio.Data.Plugins = original }()
+
+	req := httptest.NewRequest("GET", "/api/plugins", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handlePlugins(httptest.NewRecorder(), req)
+	}
+}
+
+// BenchmarkHandlePluginsBuffered measures marshalling the whole list before writing, for comparison
+func BenchmarkHandlePluginsBuffered(b *testing.B) {
+	original := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(500, 100)
+	defer func() { audio.Data.Plugins = original }()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		data, err := json.Marshal(audio.Data.Plugins)
+		if err != nil {
+			b.Fatalf("Failed to marshal plugins: %v", err)
+		}
+		w.Write(data)
+	}
+}
+
+// =============================================================================
+// COMPRESSION TESTS
+// =============================================================================
+
+// TestGzipLargePluginsResponse verifies large JSON responses are gzipped when requested
+func TestGzipLargePluginsResponse(t *testing.T) {
+	original := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(50, 20)
+	defer func() { audio.Data.Plugins = original }()
+
+	handler := gzipMiddleware(setupRoutes())
+
+	req := httptest.NewRequest("GET", "/api/plugins", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected gzip Content-Encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+
+	reader, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Response is not valid gzip: %v", err)
+	}
+	var plugins []audio.Plugin
+	if err := json.NewDecoder(reader).Decode(&plugins); err != nil {
+		t.Fatalf("Failed to decode decompressed plugins: %v", err)
+	}
+	if len(plugins) != 50 {
+		t.Errorf("Expected 50 plugins, got %d", len(plugins))
+	}
+	t.Logf("✅ /api/plugins gzipped and decoded %d plugins", len(plugins))
+}
+
+// TestGzipSkippedWithoutAcceptEncoding verifies clients without gzip get plain JSON
+func TestGzipSkippedWithoutAcceptEncoding(t *testing.T) {
+	original := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(50, 20)
+	defer func() { audio.Data.Plugins = original }()
+
+	handler := gzipMiddleware(setupRoutes())
+
+	req := httptest.NewRequest("GET", "/api/plugins", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("Expected no Content-Encoding, got %q", w.Header().Get("Content-Encoding"))
+	}
+	var plugins []audio.Plugin
+	if err := json.Unmarshal(w.Body.Bytes(), &plugins); err != nil {
+		t.Fatalf("Failed to decode plain plugins: %v", err)
+	}
+}
+
+// TestGzipETagIsWeak verifies the ETag shared by gzip and identity encodings is
+// weak, revalidates across encodings and comes with a single Vary header
+func TestGzipETagIsWeak(t *testing.T) {
+	original := audio.Data.Plugins
+	audio.Data.Plugins = makeSyntheticPlugins(50, 20)
+	defer func() { audio.Data.Plugins = original }()
+
+	handler := gzipMiddleware(setupRoutes())
+
+	gzipped := httptest.NewRequest("GET", "/api/plugins", nil)
+	gzipped.Header.Set("Accept-Encoding", "gzip")
+	w1 := httptest.NewRecorder()
+	handler.ServeHTTP(w1, gzipped)
+	if w1.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected a gzipped response, got %q", w1.Header().Get("Content-Encoding"))
+	}
+	etag := w1.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Errorf("Expected a weak ETag, got %q", etag)
+	}
+	if vary := w1.Header().Values("Vary"); len(vary) != 1 || vary[0] != "Accept-Encoding" {
+		t.Errorf("Expected a single Vary: Accept-Encoding, got %q", vary)
+	}
+
+	plain := httptest.NewRequest("GET", "/api/plugins", nil)
+	plain.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+	handler.ServeHTTP(w2, plain)
+	if w2.Code != http.StatusNotModified {
+		t.Errorf("Expected the gzip ETag to revalidate the identity response, got %d", w2.Code)
+	}
+	if w2.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Expected Vary: Accept-Encoding on the 304, got %q", w2.Header().Get("Vary"))
+	}
+}
+
+// TestGzipSmallResponseUncompressed verifies responses below the threshold are sent as-is
+func TestGzipSmallResponseUncompressed(t *testing.T) {
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+
+	req := httptest.NewRequest("GET", "/api/health", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("Expected small response to be uncompressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if w.Body.String() != `{"status":"healthy"}` {
+		t.Errorf("Unexpected body: %s", w.Body.String())
+	}
+}
+
+// TestGzipNeverCompressesEventStream verifies SSE responses pass through unbuffered
+func TestGzipNeverCompressesEventStream(t *testing.T) {
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: " + strings.Repeat("x", 4*gzipMinSize) + "\n\n"))
+		w.(http.Flusher).Flush()
+	}))
+
+	req := httptest.NewRequest("GET", "/api/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("Expected event stream to be uncompressed, got %q", w.Header().Get("Content-Encoding"))
+	}
+	if !strings.HasPrefix(w.Body.String(), "data: ") {
+		t.Errorf("Expected raw event data, got %q", w.Body.String()[:20])
+	}
+	if !w.Flushed {
+		t.Error("Expected event stream to be flushed")
+	}
+}
+
+// =============================================================================
+// CACHING TESTS
+// =============================================================================
+
+// TestDevicesETagNotModified verifies a 200-then-304 sequence on /api/devices
+func TestDevicesETagNotModified(t *testing.T) {
+	original := audio.Data.Devices
+	audio.Data.Devices = audio.DevicesData{
+		AudioOutput: []audio.AudioDevice{
+			{DeviceID: 87, UID: "device_87", Name: "External Headphones", IsOnline: true, IsDefault: true},
+		},
+		Defaults: audio.DefaultDevices{DefaultOutput: 87},
+	}
+	defer func() { audio.Data.Devices = original }()
+
+	router := setupRoutes()
+
+	req1 := httptest.NewRequest("GET", "/api/devices", nil)
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, req1)
+
+	if w1.Code != http.StatusOK {
+		t.Fatalf("Expected 200 on first request, got %d", w1.Code)
+	}
+	etag := w1.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag header on first response")
+	}
+	t.Logf("📍 First response ETag: %s", etag)
+
+	req2 := httptest.NewRequest("GET", "/api/devices", nil)
+	req2.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+
+	if w2.Code != http.StatusNotModified {
+		t.Fatalf("Expected 304 with matching If-None-Match, got %d", w2.Code)
+	}
+	if w2.Body.Len() != 0 {
+		t.Errorf("Expected empty body on 304, got %d bytes", w2.Body.Len())
+	}
+
+	// A refresh of unchanged hardware keeps the ETag valid
+	audio.Data.Devices.Timestamp = "2025-07-31 19:00:00 +0000"
+	audio.Data.Devices.Sequence++
+	reqRefreshed := httptest.NewRequest("GET", "/api/devices", nil)
+	reqRefreshed.Header.Set("If-None-Match", etag)
+	wRefreshed := httptest.NewRecorder()
+	router.ServeHTTP(wRefreshed, reqRefreshed)
+	if wRefreshed.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 after a timestamp-only refresh, got %d", wRefreshed.Code)
+	}
+
+	// A device change must invalidate the ETag
+	audio.Data.Devices.AudioOutput[0].IsOnline = false
+
+	req3 := httptest.NewRequest("GET", "/api/devices", nil)
+	req3.Header.Set("If-None-Match", etag)
+	w3 := httptest.NewRecorder()
+	router.ServeHTTP(w3, req3)
+
+	if w3.Code != http.StatusOK {
+		t.Fatalf("Expected 200 after devices changed, got %d", w3.Code)
+	}
+	if w3.Header().Get("ETag") == etag {
+		t.Error("Expected ETag to change when devices change")
+	}
+	t.Log("✅ ETag revalidation works: 200 → 304 → 200 after change")
+}
+
+// =============================================================================
+// STATIC ASSET TESTS
+// =============================================================================
+
+// TestStaticServesEmbeddedAssets verifies the embedded frontend is served when the on-disk copy is absent
+func TestStaticServesEmbeddedAssets(t *testing.T) {
+	originalDir, originalPrefer := audio.DataDir, preferDiskAssets
+	audio.DataDir = t.TempDir() // no frontend/static here
+	preferDiskAssets = true
+	defer func() { audio.DataDir, preferDiskAssets = originalDir, originalPrefer }()
+
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for embedded index, got %d", w.Code)
+	}
+	if !contains(w.Body.String(), "Rackless Audio Control") {
+		t.Errorf("Expected embedded index.html content, got: %.100s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/wasm_exec.js", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for embedded wasm_exec.js, got %d", w.Code)
+	}
+	t.Log("✅ Embedded assets served without on-disk frontend")
+}
+
+// TestDebugDashboardRoute verifies /debug renders the dashboard with the loaded devices
+func TestDebugDashboardRoute(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/debug", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for /debug, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/html") {
+		t.Errorf("Expected HTML content type, got %q", got)
+	}
+
+	body := w.Body.String()
+	for _, expected := range []string{
+		"Available Audio Devices",
+		`<div class="device online"><strong>145:</strong> Steep II`,
+		"External Headphones (DEFAULT)",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected dashboard to contain %q", expected)
+		}
+	}
+}
+
+// TestDebugDevicesFromAudioDevices verifies converted devices report the audio device fields
+func TestDebugDevicesFromAudioDevices(t *testing.T) {
+	useTestDevices(t)
+
+	devices := toDebugDevices(audio.Data.Devices.AudioOutput)
+	if len(devices) != len(audio.Data.Devices.AudioOutput) {
+		t.Fatalf("Expected %d devices, got %d", len(audio.Data.Devices.AudioOutput), len(devices))
+	}
+
+	headphones := devices[0]
+	if headphones.GetDeviceID() != 87 || headphones.GetName() != "External Headphones" {
+		t.Errorf("Unexpected device identity: %d %s", headphones.GetDeviceID(), headphones.GetName())
+	}
+	if !headphones.IsDeviceDefault() || !headphones.IsDeviceOnline() {
+		t.Error("Expected default online device")
+	}
+	if rates := headphones.GetSupportedSampleRates(); len(rates) != 4 || rates[1] != 48000 {
+		t.Errorf("Unexpected sample rates: %v", rates)
+	}
+}
+
+// =============================================================================
+// LOGGING TESTS
+// =============================================================================
+
+// TestLogLevelUpdateAppliesLive verifies PUT /api/server/log-level changes the effective level
+func TestLogLevelUpdateAppliesLive(t *testing.T) {
+	originalLogger, originalLevel := slog.Default(), logging.Level()
+	defer func() {
+		slog.SetDefault(originalLogger)
+		logging.SetLevel(originalLevel)
+	}()
+
+	var logs bytes.Buffer
+	if err := logging.Setup(&logs, "info", logging.FormatText); err != nil {
+		t.Fatalf("Failed to set up logging: %v", err)
+	}
+
+	router := setupRoutes()
+
+	req := httptest.NewRequest("PUT", "/api/server/log-level", strings.NewReader(`{"level":"error"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if logging.Level() != "error" {
+		t.Fatalf("Expected effective level error, got %s", logging.Level())
+	}
+
+	logs.Reset()
+	slog.Info("should be suppressed")
+	if logs.Len() != 0 {
+		t.Errorf("Expected info logs to be suppressed at error level, got: %s", logs.String())
+	}
+
+	req = httptest.NewRequest("PUT", "/api/server/log-level", strings.NewReader(`{"level":"chatty"}`))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown level, got %d", w.Code)
+	}
+	if logging.Level() != "error" {
+		t.Errorf("Expected level to remain error, got %s", logging.Level())
+	}
+	t.Log("✅ Log level updated without restart")
+}
+
+// =============================================================================
+// DEVICE CAPABILITY TESTS
+// =============================================================================
+
+// useTestDevices replaces the loaded device data with a known fixture for the duration of the test
+func useTestDevices(t *testing.T) {
+	t.Helper()
+	original := audio.Data.Devices
+	audio.Data.Devices = audio.DevicesData{
+		AudioInput: []audio.AudioDevice{
+			{DeviceID: 145, UID: "device_145", Name: "Steep II", ChannelCount: 2, IsOnline: true,
+				SupportedSampleRates: []int{44100, 48000, 88200, 96000, 192000}, SupportedBitDepths: []int{24, 32}},
+			{DeviceID: 105, UID: "device_105", Name: "KATANA", ChannelCount: 4, IsOnline: true,
+				SupportedSampleRates: []int{44100, 48000, 96000}, SupportedBitDepths: []int{32}},
+		},
+		AudioOutput: []audio.AudioDevice{
+			{DeviceID: 87, UID: "device_87", Name: "External Headphones", ChannelCount: 2, IsOnline: true, IsDefault: true,
+				SupportedSampleRates: []int{44100, 48000, 88200, 96000}, SupportedBitDepths: []int{32}},
+			{DeviceID: 145, UID: "device_145", Name: "Steep II", ChannelCount: 2, IsOnline: true,
+				SupportedSampleRates: []int{44100, 48000, 96000}, SupportedBitDepths: []int{24, 32}},
+			{DeviceID: 105, UID: "device_105", Name: "KATANA", ChannelCount: 4, IsOnline: true,
+				SupportedSampleRates: []int{44100, 48000, 96000}, SupportedBitDepths: []int{32}},
+		},
+		MIDIInput: []audio.MIDIDevice{
+			{UID: "midi_744763039", Name: "KATANA", EndpointID: 5672990, IsOnline: true},
+		},
+		MIDIOutput: []audio.MIDIDevice{
+			{UID: "midi_744763039", Name: "KATANA", EndpointID: 5672991, IsOnline: true},
+		},
+		Defaults:                audio.DefaultDevices{DefaultInput: 145, DefaultOutput: 87},
+		TotalAudioInputDevices:  2,
+		TotalAudioOutputDevices: 3,
+		TotalMIDIInputDevices:   1,
+		TotalMIDIOutputDevices:  1,
+		DefaultSampleRate:       48000,
+		Timestamp:               "2025-07-31 18:46:56 +0000",
+	}
+	t.Cleanup(func() { audio.Data.Devices = original })
+}
+
+// TestDeviceCapabilitiesKnownDevice verifies the consolidated capabilities for a duplex device
+func TestDeviceCapabilitiesKnownDevice(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/devices/device_145/capabilities", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var capabilities audio.DeviceCapabilities
+	if err := json.Unmarshal(w.Body.Bytes(), &capabilities); err != nil {
+		t.Fatalf("Failed to parse capabilities: %v", err)
+	}
+
+	if capabilities.Input == nil || capabilities.Output == nil {
+		t.Fatalf("Expected both input and output capabilities, got %+v", capabilities)
+	}
+	if !capabilities.IsDefault || !capabilities.Input.IsDefault || capabilities.Output.IsDefault {
+		t.Errorf("Expected device to be default input only, got %+v", capabilities)
+	}
+
+	expectedRates := []int{44100, 48000, 96000}
+	if fmt.Sprint(capabilities.SampleRates) != fmt.Sprint(expectedRates) {
+		t.Errorf("Expected joint sample rates %v, got %v", expectedRates, capabilities.SampleRates)
+	}
+	if fmt.Sprint(capabilities.BitDepths) != fmt.Sprint([]int{24, 32}) {
+		t.Errorf("Expected joint bit depths [24 32], got %v", capabilities.BitDepths)
+	}
+	t.Logf("✅ Capabilities for %s: rates %v, bit depths %v", capabilities.Name, capabilities.SampleRates, capabilities.BitDepths)
+}
+
+// TestDeviceCapabilitiesUnknownDevice verifies unknown UIDs return 404
+func TestDeviceCapabilitiesUnknownDevice(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/devices/device_999/capabilities", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown device, got %d", w.Code)
+	}
+}
+
+// =============================================================================
+// DEVICE COMPATIBILITY TESTS
+// =============================================================================
+
+// suggestSampleRate calls the suggest endpoint and returns the compatibility report
+func suggestSampleRate(t *testing.T, query string) CompatibilityReport {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/audio/suggest-sample-rate?"+query, nil)
+	w := httptest.NewRecorder()
+	handleSuggestSampleRate(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		SampleRate    int                 `json:"sampleRate"`
+		Compatibility CompatibilityReport `json:"compatibility"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	return response.Compatibility
+}
+
+// TestCompatibilitySameDevice verifies a duplex device is reported glitch-free
+func TestCompatibilitySameDevice(t *testing.T) {
+	useTestDevices(t)
+
+	report := suggestSampleRate(t, "inputDevice=145&outputDevice=145")
+	if report.Clock != ClockSameDevice || !report.GlitchFree || report.RequiresResampling {
+		t.Errorf("Expected same-device glitch-free report, got %+v", report)
+	}
+}
+
+// TestCompatibilityDifferentDevices verifies separate devices are flagged as needing resampling
+func TestCompatibilityDifferentDevices(t *testing.T) {
+	useTestDevices(t)
+
+	// Output 0 resolves to the default output (External Headphones)
+	report := suggestSampleRate(t, "inputDevice=145")
+	if report.Clock != ClockIndependent || report.GlitchFree || !report.RequiresResampling {
+		t.Errorf("Expected independent clocks report, got %+v", report)
+	}
+	if report.OutputDeviceID != 87 {
+		t.Errorf("Expected default output 87 to be resolved, got %d", report.OutputDeviceID)
+	}
+}
+
+// TestCompatibilityAggregateDevice verifies an aggregate device used for both
+// input and output is treated as clock-synced
+func TestCompatibilityAggregateDevice(t *testing.T) {
+	useTestDevices(t)
+	aggregate := audio.AudioDevice{
+		DeviceID: 200, UID: "device_200", Name: "Studio Rig", ChannelCount: 4, IsOnline: true,
+		SupportedSampleRates: []int{44100, 48000}, SupportedBitDepths: []int{32},
+		TransportType: audio.TransportAggregate,
+	}
+	audio.Data.Devices.AudioInput = append(audio.Data.Devices.AudioInput, aggregate)
+	audio.Data.Devices.AudioOutput = append(audio.Data.Devices.AudioOutput, aggregate)
+
+	report := suggestSampleRate(t, "inputDevice=200&outputDevice=200")
+	if report.Clock != ClockAggregate || !report.GlitchFree || report.RequiresResampling {
+		t.Errorf("Expected aggregate glitch-free report, got %+v", report)
+	}
+}
+
+// TestCompatibilityInputOutsideAggregate verifies a separate input feeding an
+// aggregate output still drifts, whatever the aggregate is called
+func TestCompatibilityInputOutsideAggregate(t *testing.T) {
+	useTestDevices(t)
+	audio.Data.Devices.AudioOutput = append(audio.Data.Devices.AudioOutput, audio.AudioDevice{
+		DeviceID: 200, UID: "device_200", Name: "Aggregate Device", ChannelCount: 4, IsOnline: true,
+		SupportedSampleRates: []int{44100, 48000}, SupportedBitDepths: []int{32},
+		TransportType: audio.TransportAggregate,
+	})
+
+	report := suggestSampleRate(t, "inputDevice=145&outputDevice=200")
+	if report.Clock != ClockIndependent || report.GlitchFree || !report.RequiresResampling {
+		t.Errorf("Expected independent clocks report, got %+v", report)
+	}
+}
+
+// =============================================================================
+// HEADLESS (NO AUDIO HARDWARE) TESTS
+// =============================================================================
+
+// TestStartAudioDeviceInUse verifies a hogged device is rejected before launching audio-host
+func TestStartAudioDeviceInUse(t *testing.T) {
+	useTestDevices(t)
+	audio.Data.Devices.AudioInput[1].HogModePID = 4242
+
+	router := setupRoutes()
+	body := strings.NewReader(`{"config": {"sampleRate": 48000, "audioInputDeviceID": 105}}`)
+	req := httptest.NewRequest("POST", "/api/audio/start", body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 for hogged device, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "Device KATANA is in use by PID 4242") {
+		t.Errorf("Expected device in use message, got: %s", w.Body.String())
+	}
+}
+
+// TestValidateSampleRateSuggestsNearest verifies an unsupported rate names the closest supported one
+func TestValidateSampleRateSuggestsNearest(t *testing.T) {
+	useTestDevices(t)
+
+	err := validateSampleRate(audio.AudioConfig{SampleRate: 50000})
+	if err == nil || !strings.Contains(err.Error(), "nearest supported: 48000 Hz") {
+		t.Errorf("Expected a nearest rate suggestion, got: %v", err)
+	}
+}
+
+// TestStartAudioWithoutOutputDevices verifies a clear error when no output device exists
+func TestStartAudioWithoutOutputDevices(t *testing.T) {
+	useTestDevices(t)
+	audio.Data.Devices.AudioOutput = nil
+	audio.Data.Devices.TotalAudioOutputDevices = 0
+
+	err := validateSampleRate(audio.AudioConfig{SampleRate: 48000})
+	if err == nil || !strings.Contains(err.Error(), "no output device available") {
+		t.Fatalf("Expected no output device error, got: %v", err)
+	}
+
+	router := setupRoutes()
+	body := strings.NewReader(`{"config": {"sampleRate": 48000}}`)
+	req := httptest.NewRequest("POST", "/api/audio/start", body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 without output devices, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "no output device available") {
+		t.Errorf("Expected response to explain missing output device, got: %s", w.Body.String())
+	}
+	t.Logf("✅ Start rejected on headless machine: %v", err)
+}
+
+// =============================================================================
+// MIDI OUTPUT TESTS
+// =============================================================================
+
+// postMIDISend sends a MIDI send request body and returns the recorder
+func postMIDISend(t *testing.T, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupRoutes()
+	req := httptest.NewRequest("POST", "/api/midi/send", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestMIDISendNotSupported verifies a valid request reaches the stub and reports 501
+func TestMIDISendNotSupported(t *testing.T) {
+	useTestDevices(t)
+
+	w := postMIDISend(t, `{"deviceUID": "midi_744763039", "channel": 1, "cc": 74, "value": 100}`)
+	if w.Code != http.StatusNotImplemented {
+		t.Fatalf("Expected 501 from MIDI stub, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "not supported") {
+		t.Errorf("Expected not supported error, got: %s", w.Body.String())
+	}
+}
+
+// TestMIDISendValidation verifies bad requests and unknown devices are rejected before sending
+func TestMIDISendValidation(t *testing.T) {
+	useTestDevices(t)
+
+	if w := postMIDISend(t, `{"deviceUID": "midi_744763039", "channel": 17, "cc": 74, "value": 100}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid channel, got %d", w.Code)
+	}
+	if w := postMIDISend(t, `{"deviceUID": "midi_missing", "channel": 1, "cc": 74, "value": 100}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown device, got %d", w.Code)
+	}
+}
+
+// TestMIDIClockProbeOnRequest verifies the clock probe endpoint reaches the stub for known inputs
+func TestMIDIClockProbeOnRequest(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/midi/midi_744763039/clock?window=200", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 from clock probe stub, got %d: %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/midi/midi_missing/clock", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown MIDI input, got %d", w.Code)
+	}
+}
+
+// =============================================================================
+// SERVER DATA REFRESH TESTS
+// =============================================================================
+
+// writeTestTool installs an executable shell script under dir at the given relative path
+func writeTestTool(t *testing.T, dir, relative, script string) {
+	t.Helper()
+	path := filepath.Join(dir, relative)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create tool directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write test tool: %v", err)
+	}
+}
+
+// TestServerDataRefresh verifies refresh=true re-runs both the devices and inspector tools
+func TestServerDataRefresh(t *testing.T) {
+	useTestDevices(t)
+	originalPlugins, originalDir := audio.Data.Plugins, audio.DataDir
+	t.Cleanup(func() {
+		audio.Data.Plugins = originalPlugins
+		audio.DataDir = originalDir
+	})
+
+	dir := t.TempDir()
+	audio.DataDir = dir
+	writeTestTool(t, dir, audio.DevicesToolPath, `echo '{"audioOutput": [{"deviceId": 99, "uid": "device_99", "name": "Refreshed Output", "channelCount": 2, "isOnline": true}], "totalAudioOutputDevices": 1}'
+`)
+	writeTestTool(t, dir, audio.InspectorToolPath, `echo '[{"name": "Refreshed Plugin", "type": "aufx", "subtype": "dely", "manufacturerID": "appl", "parameters": []}]'
+`)
+
+	router := setupRoutes()
+	req := httptest.NewRequest("GET", "/api/data", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "Refreshed") {
+		t.Fatal("Expected cached data without refresh=true")
+	}
+
+	req = httptest.NewRequest("GET", "/api/data?refresh=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Refresh failed with status %d: %s", w.Code, w.Body.String())
+	}
+
+	var data audio.ServerData
+	if err := json.NewDecoder(w.Body).Decode(&data); err != nil {
+		t.Fatalf("Failed to decode server data: %v", err)
+	}
+	if len(data.Devices.AudioOutput) != 1 || data.Devices.AudioOutput[0].Name != "Refreshed Output" {
+		t.Errorf("Expected refreshed devices, got %+v", data.Devices.AudioOutput)
+	}
+	if len(data.Plugins) != 1 || data.Plugins[0].Name != "Refreshed Plugin" {
+		t.Errorf("Expected refreshed plugins, got %+v", data.Plugins)
+	} else if data.Plugins[0].Category != "Effect" {
+		t.Errorf("Expected aufx plugin categorised as Effect, got %q", data.Plugins[0].Category)
+	}
+}
+
+// TestServerDataRefreshTimeout verifies ?timeout= overrides the tool timeout for one refresh
+func TestServerDataRefreshTimeout(t *testing.T) {
+	useTestDevices(t)
+	originalPlugins, originalDir, originalTimeout := audio.Data.Plugins, audio.DataDir, audio.ToolTimeout
+	t.Cleanup(func() {
+		audio.Data.Plugins = originalPlugins
+		audio.DataDir = originalDir
+		audio.ToolTimeout = originalTimeout
+	})
+
+	dir := t.TempDir()
+	audio.DataDir = dir
+	audio.ToolTimeout = 50 * time.Millisecond
+	writeTestTool(t, dir, audio.DevicesToolPath, `sleep 0.3
+echo '{"audioOutput": [{"deviceId": 99, "uid": "device_99", "name": "Slow Output", "channelCount": 2, "isOnline": true}]}'
+`)
+	writeTestTool(t, dir, audio.InspectorToolPath, `echo '[]'
+`)
+	router := setupRoutes()
+
+	tests := []struct {
+		name    string
+		timeout string
+		status  int
+	}{
+		{"short timeout fails", "100ms", http.StatusGatewayTimeout},
+		{"longer timeout succeeds", "5s", http.StatusOK},
+		{"invalid timeout", "soon", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/data?refresh=true&timeout="+tt.timeout, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// TestParseRefreshTimeoutClamps verifies oversized timeouts are capped
+func TestParseRefreshTimeoutClamps(t *testing.T) {
+	timeout, err := parseRefreshTimeout("1h")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if timeout != maxRefreshTimeout {
+		t.Errorf("Expected %v, got %v", maxRefreshTimeout, timeout)
+	}
+}
+
+// =============================================================================
+// PLUGIN PARAMETER PAGINATION TESTS
+// =============================================================================
+
+// usePlugins replaces the loaded plugins for the duration of the test
+func usePlugins(t *testing.T, plugins []audio.Plugin) {
+	t.Helper()
+	original := audio.Data.Plugins
+	audio.Data.Plugins = plugins
+	t.Cleanup(func() { audio.Data.Plugins = original })
+}
+
+// TestPluginSummaryVsFull verifies summary=true drops the parameter list
+func TestPluginSummaryVsFull(t *testing.T) {
+	usePlugins(t, makeSyntheticPlugins(2, 120))
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/plugins/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var full audio.Plugin
+	if err := json.NewDecoder(w.Body).Decode(&full); err != nil {
+		t.Fatalf("Failed to decode full plugin: %v", err)
+	}
+	if len(full.Parameters) != 120 {
+		t.Errorf("Expected 120 parameters in full plugin, got %d", len(full.Parameters))
+	}
+
+	req = httptest.NewRequest("GET", "/api/plugins/1?summary=true", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), `"parameters"`) {
+		t.Error("Expected summary to omit the parameter list")
+	}
+	var summary audio.PluginSummary
+	if err := json.NewDecoder(w.Body).Decode(&summary); err != nil {
+		t.Fatalf("Failed to decode plugin summary: %v", err)
+	}
+	if summary.Name != full.Name || summary.ParameterCount != 120 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
+
+// getParameterPage fetches a page of plugin parameters and returns the recorder
+func getParameterPage(t *testing.T, router http.Handler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/api/plugins/0/parameters"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestPluginParametersPagination verifies paging, the final partial page and out-of-range offsets
+func TestPluginParametersPagination(t *testing.T) {
+	usePlugins(t, makeSyntheticPlugins(1, 120))
+	router := setupRoutes()
+
+	cases := []struct {
+		query         string
+		expectedCount int
+		firstAddress  int
+	}{
+		{"", 50, 0},
+		{"?offset=50&limit=50", 50, 50},
+		{"?offset=100&limit=50", 20, 100},
+		{"?offset=500", 0, -1},
+	}
+
+	for _, tc := range cases {
+		w := getParameterPage(t, router, tc.query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: expected 200, got %d: %s", tc.query, w.Code, w.Body.String())
+		}
+
+		var page PluginParametersPage
+		if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+			t.Fatalf("%q: failed to decode page: %v", tc.query, err)
+		}
+		if page.Total != 120 || len(page.Parameters) != tc.expectedCount {
+			t.Errorf("%q: expected %d of 120 parameters, got %d of %d", tc.query, tc.expectedCount, len(page.Parameters), page.Total)
+		}
+		if tc.expectedCount > 0 && page.Parameters[0].Address != tc.firstAddress {
+			t.Errorf("%q: expected first address %d, got %d", tc.query, tc.firstAddress, page.Parameters[0].Address)
+		}
+	}
+}
+
+// TestPluginParametersPaginationBounds verifies invalid offsets and limits are rejected
+func TestPluginParametersPaginationBounds(t *testing.T) {
+	usePlugins(t, makeSyntheticPlugins(1, 10))
+	router := setupRoutes()
+
+	for _, query := range []string{"?offset=-1", "?limit=0", "?limit=501", "?limit=ten"} {
+		if w := getParameterPage(t, router, query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// stubLoadDevices replaces boot-time device enumeration with failures followed by the fixture
+func stubLoadDevices(t *testing.T, failures int) *int {
+	t.Helper()
+	originalLoad, originalDelay := loadDevices, deviceLoadRetryDelay
+	calls := 0
+	loadDevices = func(context.Context) error {
+		calls++
+		if calls <= failures {
+			return fmt.Errorf("AudioObjectGetPropertyData failed")
+		}
+		useTestDevices(t)
+		return nil
+	}
+	deviceLoadRetryDelay = 0
+	t.Cleanup(func() {
+		loadDevices, deviceLoadRetryDelay = originalLoad, originalDelay
+		setDegraded("")
+	})
+	return &calls
+}
+
+// TestInitDevicesRecoversOnRetry verifies a transient enumeration failure is retried
+func TestInitDevicesRecoversOnRetry(t *testing.T) {
+	calls := stubLoadDevices(t, 1)
+
+	initDevices()
+
+	if *calls != 2 {
+		t.Errorf("Expected 2 enumeration attempts, got %d", *calls)
+	}
+	if degraded, reason := degradedStatus(); degraded {
+		t.Errorf("Expected healthy after retry, got degraded: %s", reason)
+	}
+	if len(audio.Data.Devices.AudioOutput) == 0 {
+		t.Error("Expected devices loaded after retry")
+	}
+}
+
+// TestInitDevicesDegradedMode verifies persistent failures start the server degraded instead of exiting
+func TestInitDevicesDegradedMode(t *testing.T) {
+	original := audio.Data.Devices
+	t.Cleanup(func() { audio.Data.Devices = original })
+	calls := stubLoadDevices(t, deviceLoadAttempts)
+
+	initDevices()
+
+	if *calls != deviceLoadAttempts {
+		t.Errorf("Expected %d enumeration attempts, got %d", deviceLoadAttempts, *calls)
+	}
+	if len(audio.Data.Devices.AudioInput)+len(audio.Data.Devices.AudioOutput) != 0 {
+		t.Error("Expected an empty device set in degraded mode")
+	}
+
+	router := setupRoutes()
+	req := httptest.NewRequest("GET", "/api/health", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var health map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&health); err != nil {
+		t.Fatalf("Failed to decode health: %v", err)
+	}
+	if health["status"] != "degraded" || health["degraded"] != true {
+		t.Errorf("Expected degraded health, got %v", health)
+	}
+}
+
+// TestInitServerDataDegradesOnDeviceFailure drives boot in main's order and
+// verifies a device enumeration failure degrades rather than aborting startup
+func TestInitServerDataDegradesOnDeviceFailure(t *testing.T) {
+	originalDevices, originalPlugins := audio.Data.Devices, audio.Data.Plugins
+	originalDir, originalReconfig := audio.DataDir, audio.Reconfig
+	t.Cleanup(func() {
+		audio.Data.Devices, audio.Data.Plugins = originalDevices, originalPlugins
+		audio.DataDir, audio.Reconfig = originalDir, originalReconfig
+	})
+	stubLoadDevices(t, deviceLoadAttempts)
+
+	dir := t.TempDir()
+	audio.DataDir = dir
+	writeTestTool(t, dir, audio.InspectorToolPath, `echo '[{"name": "Boot Plugin", "type": "aufx", "subtype": "dely", "manufacturerID": "appl", "parameters": []}]'
+`)
+	audio.Reconfig = nil
+
+	if err := initServerData(); err != nil {
+		t.Fatalf("Expected startup to continue without devices, got: %v", err)
+	}
+	if audio.Reconfig == nil {
+		t.Error("Expected the configuration manager to be created")
+	}
+	if degraded, _ := degradedStatus(); !degraded {
+		t.Error("Expected degraded mode after enumeration failed")
+	}
+	if plugins := audio.Plugins(); len(plugins) != 1 || plugins[0].Name != "Boot Plugin" {
+		t.Errorf("Expected plugins loaded despite device failure, got %+v", plugins)
+	}
+}
+
+// TestServerDataRefreshAbortsOnDisconnect verifies a cancelled request stops waiting on enumeration
+func TestServerDataRefreshAbortsOnDisconnect(t *testing.T) {
+	original := loadDevices
+	t.Cleanup(func() { loadDevices = original })
+	loadDevices = func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/api/data?refresh=true", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	router := setupRoutes()
+
+	done := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Handler kept waiting on enumeration after the client went away")
+	}
+}
+
+// TestPluginReadsDuringRescan verifies plugin handlers don't race a rescan swapping the list (run with -race)
+func TestPluginReadsDuringRescan(t *testing.T) {
+	usePlugins(t, makeSyntheticPlugins(3, 10))
+	router := setupRoutes()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			audio.SetPlugins(makeSyntheticPlugins(3+i%2, 10))
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		for _, path := range []string{"/api/plugins", "/api/plugins/0", "/api/plugins/1/parameters"} {
+			req := httptest.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: expected 200 during rescan, got %d", path, w.Code)
+			}
+		}
+	}
+	<-done
+}
+
+// TestOversizedRequestBodyRejected verifies POST handlers answer 413 instead of reading unbounded bodies
+func TestOversizedRequestBodyRejected(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	// A syntactically valid prefix followed by far more data than any limit allows
+	oversized := `{"config": {"sampleRate": 48000}, "padding": "` + strings.Repeat("x", 2*maxConfigBodyBytes) + `"}`
+
+	for _, path := range []string{"/api/audio/start", "/api/audio/switch-devices", "/api/midi/send"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(oversized))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("%s: expected 413, got %d: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	// Small invalid bodies are still plain bad requests
+	req := httptest.NewRequest("POST", "/api/audio/start", strings.NewReader("{"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", w.Code)
+	}
+}
+
+// TestConfigChangeRejectsInvalidSampleRate verifies zero, negative, NaN and extreme rates fail validation
+func TestConfigChangeRejectsInvalidSampleRate(t *testing.T) {
+	useTestDevices(t)
+
+	for _, rate := range []float64{0, -44100, math.NaN(), 10_000_000} {
+		err := validateAudioConfig(audio.AudioConfig{SampleRate: rate, BufferSize: 256})
+		if err == nil || !strings.Contains(err.Error(), "invalid sample rate") {
+			t.Errorf("Rate %v: expected invalid sample rate error, got: %v", rate, err)
+		}
+	}
+
+	router := setupRoutes()
+	for _, body := range []string{`{"config": {"sampleRate": 0}}`, `{"config": {"sampleRate": -48000}}`} {
+		req := httptest.NewRequest("POST", "/api/audio/config-change", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestVersionedAPIPrefix verifies routes answer under /api/v1 and the unversioned path is marked deprecated
+func TestVersionedAPIPrefix(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/v1/devices", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected /api/v1/devices to answer 200, got %d", w.Code)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Error("Expected no Deprecation header on the versioned path")
+	}
+	versioned := w.Body.String()
+
+	req = httptest.NewRequest("GET", "/api/devices", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected /api/devices to answer 200, got %d", w.Code)
+	}
+	if w.Header().Get("Deprecation") != "true" {
+		t.Error("Expected a Deprecation header on the unversioned path")
+	}
+	if link := w.Header().Get("Link"); link != `</api/v1/devices>; rel="successor-version"` {
+		t.Errorf("Expected a successor Link, got %q", link)
+	}
+	if w.Body.String() != versioned {
+		t.Error("Expected both paths to serve the same devices")
+	}
+
+	// Wildcard routes resolve under the prefix too
+	req = httptest.NewRequest("GET", "/api/v1/devices/device_87/capabilities", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected versioned wildcard route to resolve, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestInvalidJSONNamesField verifies decode errors point the client at the bad field or position
+func TestInvalidJSONNamesField(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	tests := []struct {
+		name   string
+		body   string
+		field  string
+		detail string
+	}{
+		{"wrong-typed nested field", `{"config": {"sampleRate": "fast"}}`, "config.sampleRate", "float64"},
+		{"wrong-typed top-level field", `{"config": {}, "safeMode": "yes"}`, "safeMode", "bool"},
+		{"syntax error", `{"config": {"sampleRate": 48000,}}`, "", "at byte"},
+		{"empty body", ``, "", "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/audio/start", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+			var response map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Expected a JSON error envelope: %v", err)
+			}
+			if tt.field != "" && response["field"] != tt.field {
+				t.Errorf("Expected field %q, got %v", tt.field, response["field"])
+			}
+			if message, _ := response["error"].(string); !strings.Contains(message, tt.detail) {
+				t.Errorf("Expected error mentioning %q, got %q", tt.detail, message)
+			}
+		})
+	}
+}
+
+// TestStrictJSONRejectsUnknownFields verifies a typo'd field fails in strict mode and is ignored otherwise
+func TestStrictJSONRejectsUnknownFields(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+	body := `{"config": {"sampleRat": 48000}}`
+
+	// Lenient (default): the unknown field is dropped and validation sees an unset rate
+	req := httptest.NewRequest("POST", "/api/audio/config-change", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "unknown field") {
+		t.Errorf("Expected unknown fields to be ignored by default, got: %s", w.Body.String())
+	}
+
+	original := strictJSON
+	strictJSON = true
+	t.Cleanup(func() { strictJSON = original })
+
+	req = httptest.NewRequest("POST", "/api/audio/config-change", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 in strict mode, got %d: %s", w.Code, w.Body.String())
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode error envelope: %v", err)
+	}
+	if response["field"] != "sampleRat" {
+		t.Errorf("Expected the rejected field to be named, got %v", response)
+	}
+}
+
+// TestFlushCachesReEnumerates verifies a flush re-runs device enumeration and is refused to remote clients
+func TestFlushCachesReEnumerates(t *testing.T) {
+	useTestDevices(t)
+	originalPlugins, originalDir := audio.Data.Plugins, audio.DataDir
+	t.Cleanup(func() {
+		audio.Data.Plugins = originalPlugins
+		audio.DataDir = originalDir
+	})
+	dir := t.TempDir()
+	audio.DataDir = dir
+	writeTestTool(t, dir, audio.InspectorToolPath, `echo '[]'
+`)
+	calls := stubLoadDevices(t, 0)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("POST", "/api/admin/flush-caches", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403 for a remote client, got %d: %s", w.Code, w.Body.String())
+	}
+	if *calls != 0 {
+		t.Fatalf("Expected no enumeration for a rejected flush, got %d", *calls)
+	}
+
+	req = httptest.NewRequest("POST", "/api/admin/flush-caches", nil)
+	req.RemoteAddr = "127.0.0.1:52100"
+	req.Header.Set("Origin", "https://evil.example")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected 403 for a cross-origin browser request, got %d: %s", w.Code, w.Body.String())
+	}
+	if *calls != 0 {
+		t.Fatalf("Expected no enumeration for a cross-origin flush, got %d", *calls)
+	}
+
+	req = httptest.NewRequest("POST", "/api/admin/flush-caches", nil)
+	req.RemoteAddr = "127.0.0.1:52100"
+	req.Header.Set("Origin", "http://"+req.Host)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from the server's own origin, got %d: %s", w.Code, w.Body.String())
+	}
+	if *calls != 1 {
+		t.Fatalf("Expected one enumeration after a same-origin flush, got %d", *calls)
+	}
+
+	req = httptest.NewRequest("POST", "/api/admin/flush-caches", nil)
+	req.RemoteAddr = "127.0.0.1:52100"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from localhost, got %d: %s", w.Code, w.Body.String())
+	}
+	if *calls != 2 {
+		t.Errorf("Expected the flush to hit the device enumerator again, got %d", *calls)
+	}
+}
+
+// TestDeviceCountsMatchDevices verifies the counts endpoint agrees with the full device payload
+func TestDeviceCountsMatchDevices(t *testing.T) {
+	useTestDevices(t)
+	router := setupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/devices/counts", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var counts audio.DeviceCounts
+	if err := json.NewDecoder(w.Body).Decode(&counts); err != nil {
+		t.Fatalf("Failed to decode counts: %v", err)
+	}
+
+	devices := audio.Data.Devices
+	want := audio.DeviceCounts{
+		AudioInput:  len(devices.AudioInput),
+		AudioOutput: len(devices.AudioOutput),
+		MIDIInput:   len(devices.MIDIInput),
+		MIDIOutput:  len(devices.MIDIOutput),
+		Defaults:    devices.Defaults,
+		Sequence:    devices.Sequence,
+	}
+	if counts != want {
+		t.Errorf("Expected %+v, got %+v", want, counts)
+	}
+	if counts.AudioInput == 0 || counts.MIDIInput == 0 {
+		t.Errorf("Expected the fixture's devices to be counted, got %+v", counts)
+	}
+}
@@ -9,6 +9,7 @@
 #import <AVFoundation/AVFoundation.h>
 #import <CoreMIDI/CoreMIDI.h>
 #import <AudioUnit/AudioUnit.h>
+#import <mach/mach_time.h>
 
 // Device Enumeration Functions
 NSString* enumerateAudioDevices(BOOL isInput) {
@@ -164,6 +165,17 @@ typedef struct {
     
     // Audio input buffer
     AudioBufferList* inputBufferList;
+    
+    // Input level of the most recent buffer (linear, written by the render thread)
+    volatile float inputPeakLevel;
+    volatile float inputRMSLevel;
+    
+    // Smoothed fraction of each buffer's time budget spent rendering (0-1)
+    volatile double dspLoad;
+    
+    // Dropouts: renders that overran their budget or gaps in the sample timeline
+    volatile int xrunCount;
+    Float64 lastSampleTime;
 }
 
 - (instancetype)initWithConfig:(AudioHostConfig)config;
@@ -206,13 +218,13 @@ static OSStatus PluginInputCallback(void* inRefCon,
     return noErr;
 }
 
-// Audio render callback - runs on Core Audio's real-time thread
-static OSStatus AudioRenderCallback(void* inRefCon,
-                                   AudioUnitRenderActionFlags* ioActionFlags,
-                                   const AudioTimeStamp* inTimeStamp,
-                                   UInt32 inBusNumber,
-                                   UInt32 inNumberFrames,
-                                   AudioBufferList* ioData) {
+// Audio render callback body - see AudioRenderCallback for load measurement
+static OSStatus RenderAudio(void* inRefCon,
+                            AudioUnitRenderActionFlags* ioActionFlags,
+                            const AudioTimeStamp* inTimeStamp,
+                            UInt32 inBusNumber,
+                            UInt32 inNumberFrames,
+                            AudioBufferList* ioData) {
     
     AudioHostEngine* engine = (__bridge AudioHostEngine*)inRefCon;
     
@@ -276,6 +288,7 @@ static OSStatus AudioRenderCallback(void* inRefCon,
             
             static int debugCounter = 0;
             float maxInputLevel = 0.0f;
+            float sumSquares = 0.0f;
             
             // If plugin is loaded, process through plugin first
             if (engine->pluginLoaded && engine->pluginUnit && engine->pluginInputData) {
@@ -286,11 +299,12 @@ static OSStatus AudioRenderCallback(void* inRefCon,
                 for (UInt32 frame = 0; frame < inNumberFrames; frame++) {
                     Float32 rawSample = inputBuffer[frame * 2 + engine->audioInputChannel];
                     
-                    // Track max input level for debugging
+                    // Track max input level for debugging and metering
                     float absLevel = fabsf(rawSample);
                     if (absLevel > maxInputLevel) {
                         maxInputLevel = absLevel;
                     }
+                    sumSquares += rawSample * rawSample;
                     
                     // Send mono guitar to both plugin input channels
                     pluginInputBuffer[frame * 2] = rawSample;     // Left channel
@@ -329,11 +343,12 @@ static OSStatus AudioRenderCallback(void* inRefCon,
                     Float32 rawSample = inputBuffer[frame * 2 + engine->audioInputChannel];
                     Float32 guitarSample = rawSample * guitarGain;
                     
-                    // Track max input level for debugging
+                    // Track max input level for debugging and metering
                     float absLevel = fabsf(rawSample);
                     if (absLevel > maxInputLevel) {
                         maxInputLevel = absLevel;
                     }
+                    sumSquares += rawSample * rawSample;
                     
                     // Send to both output channels (mono guitar to stereo output)
                     outputBuffer[frame * 2] = guitarSample;     // Left channel
@@ -341,6 +356,10 @@ static OSStatus AudioRenderCallback(void* inRefCon,
                 }
             }
             
+            // Publish levels for get-input-level
+            engine->inputPeakLevel = maxInputLevel;
+            engine->inputRMSLevel = sqrtf(sumSquares / inNumberFrames);
+            
             // Debug input levels every few seconds
             debugCounter++;
             if (debugCounter >= 2000) { // Print every ~2 seconds at 44.1kHz with 256 buffer
@@ -380,6 +399,49 @@ static OSStatus AudioRenderCallback(void* inRefCon,
     return noErr;
 }
 
+// Audio render callback - runs on Core Audio's real-time thread.
+// Times each render against the buffer's duration to report DSP load, and
+// counts an xrun whenever a render overruns or the sample timeline skips.
+static OSStatus AudioRenderCallback(void* inRefCon,
+                                   AudioUnitRenderActionFlags* ioActionFlags,
+                                   const AudioTimeStamp* inTimeStamp,
+                                   UInt32 inBusNumber,
+                                   UInt32 inNumberFrames,
+                                   AudioBufferList* ioData) {
+    static mach_timebase_info_data_t timebase;
+    if (timebase.denom == 0) {
+        mach_timebase_info(&timebase);
+    }
+    
+    uint64_t startTime = mach_absolute_time();
+    OSStatus status = RenderAudio(inRefCon, ioActionFlags, inTimeStamp, inBusNumber, inNumberFrames, ioData);
+    uint64_t elapsedNanos = (mach_absolute_time() - startTime) * timebase.numer / timebase.denom;
+    
+    AudioHostEngine* engine = (__bridge AudioHostEngine*)inRefCon;
+    BOOL xrun = NO;
+    if (engine->sampleRate > 0 && inNumberFrames > 0) {
+        double budgetNanos = (double)inNumberFrames / engine->sampleRate * 1e9;
+        double load = (double)elapsedNanos / budgetNanos;
+        // Exponential smoothing so one slow buffer doesn't dominate the reading
+        engine->dspLoad = engine->dspLoad * 0.9 + load * 0.1;
+        xrun = load > 1.0;
+    }
+    
+    if (inTimeStamp && (inTimeStamp->mFlags & kAudioTimeStampSampleTimeValid)) {
+        Float64 sampleTime = inTimeStamp->mSampleTime;
+        if (engine->lastSampleTime >= 0 && sampleTime - engine->lastSampleTime > inNumberFrames + 0.5) {
+            xrun = YES;
+        }
+        engine->lastSampleTime = sampleTime;
+    }
+    
+    if (xrun) {
+        engine->xrunCount++;
+    }
+    
+    return status;
+}
+
 @implementation AudioHostEngine
 
 - (instancetype)initWithConfig:(AudioHostConfig)config {
@@ -404,6 +466,10 @@ static OSStatus AudioRenderCallback(void* inRefCon,
         testTonePhase = 0.0;
         testToneFrequency = 440.0; // A4 note
         
+        // Dropout tracking (no previous render yet)
+        xrunCount = 0;
+        lastSampleTime = -1;
+        
         NSLog(@"🎵 AudioHostEngine initialized:");
         NSLog(@"   Sample Rate: %.0f Hz", sampleRate);
         NSLog(@"   Bit Depth: %d", bitDepth);
@@ -854,12 +920,22 @@ void processCommand(AudioHostEngine* engine, NSString* command) {
         }
     }
     else if ([cmd isEqualToString:@"status"]) {
-        printf("STATUS: running=%s sampleRate=%.0f bufferSize=%d testTone=%s toneFreq=%.1f\n",
+        printf("STATUS: running=%s sampleRate=%.0f bufferSize=%d testTone=%s toneFreq=%.1f dspLoad=%.2f xruns=%d\n",
                [engine isRunning] ? "true" : "false",
                engine->sampleRate,
                engine->bufferSize,
                engine->enableTestTone ? "true" : "false",
-               engine->testToneFrequency);
+               engine->testToneFrequency,
+               engine->dspLoad,
+               engine->xrunCount);
+    }
+    else if ([cmd isEqualToString:@"get-input-level"]) {
+        // Levels in dBFS, floored at -120 for silence
+        float peak = engine->inputPeakLevel;
+        float rms = engine->inputRMSLevel;
+        printf("LEVEL: peak=%.1f rms=%.1f\n",
+               peak > 0.000001f ? 20.0f * log10f(peak) : -120.0f,
+               rms > 0.000001f ? 20.0f * log10f(rms) : -120.0f);
     }
     else if ([cmd isEqualToString:@"tone"] && parts.count >= 2) {
         NSString* subCmd = parts[1];
@@ -937,11 +1013,16 @@ void processCommand(AudioHostEngine* engine, NSString* command) {
         printf("OK: goodbye\n");
         exit(0);
     }
+    else if ([cmd isEqualToString:@"commands"]) {
+        // Machine-readable command list, queried by the server at startup
+        printf("COMMANDS: start stop status get-input-level tone load-plugin unload-plugin list-plugins devices quit exit help commands\n");
+    }
     else if ([cmd isEqualToString:@"help"]) {
         printf("Commands:\n");
         printf("  start              - Start audio processing\n");
         printf("  stop               - Stop audio processing\n");
         printf("  status             - Get current status\n");
+        printf("  get-input-level    - Get input peak/RMS level in dBFS\n");
         printf("  tone on|off        - Enable/disable test tone\n");
         printf("  tone freq <hz>     - Set test tone frequency\n");
         printf("  load-plugin <id>   - Load plugin (format: type:subtype:manufacturer)\n");
@@ -950,6 +1031,7 @@ void processCommand(AudioHostEngine* engine, NSString* command) {
         printf("  devices <type>     - Enumerate devices (audio-input|audio-output|midi-input|midi-output)\n");
         printf("  quit|exit          - Stop and exit\n");
         printf("  help               - Show this help\n");
+        printf("  commands           - List accepted commands on one line\n");
     }
     else {
         printf("ERROR: unknown command '%s' (try 'help')\n", [cmd UTF8String]);
@@ -1008,8 +1090,10 @@ int main(int argc, const char * argv[]) {
         
         if (commandMode) {
             // Command mode: read commands from stdin, write responses to stdout
-            // Send ready signal to stderr so stdout stays clean for JSON
-            fprintf(stderr, "READY\n");
+            // Send ready signal to stderr so stdout stays clean for JSON.
+            // The payload reports what the engine actually runs at.
+            fprintf(stderr, "READY {\"sampleRate\":%.0f,\"bufferSize\":%d}\n",
+                    engine->sampleRate, engine->bufferSize);
             fflush(stderr);
             
             char buffer[1024];
@@ -8,6 +8,97 @@
 #import <CoreAudio/CoreAudio.h>
 #import <CoreMIDI/CoreMIDI.h>
 
+// Returns the PID holding exclusive (hog mode) access to the device, or -1 when free
+static pid_t getDeviceHogModePID(AudioDeviceID deviceID) {
+    AudioObjectPropertyAddress hogAddress = {
+        kAudioDevicePropertyHogMode,
+        kAudioObjectPropertyScopeGlobal,
+        kAudioObjectPropertyElementMain
+    };
+    pid_t hogPID = -1;
+    UInt32 hogSize = sizeof(pid_t);
+    OSStatus status = AudioObjectGetPropertyData(deviceID, &hogAddress, 0, NULL, &hogSize, &hogPID);
+    if (status != noErr) {
+        return -1;
+    }
+    return hogPID;
+}
+
+// Describes the virtual format of the device's first stream in the given scope as
+// {"sampleFormat": "float32", "bitsPerChannel": 32, "interleaved": true}, or NSNull
+// when the device has no streams in that scope or isn't linear PCM
+static id getDeviceStreamFormat(AudioDeviceID deviceID, AudioObjectPropertyScope scope) {
+    AudioObjectPropertyAddress streamsAddress = {
+        kAudioDevicePropertyStreams,
+        scope,
+        kAudioObjectPropertyElementMain
+    };
+    UInt32 size = 0;
+    OSStatus status = AudioObjectGetPropertyDataSize(deviceID, &streamsAddress, 0, NULL, &size);
+    if (status != noErr || size < sizeof(AudioStreamID)) {
+        return [NSNull null];
+    }
+    AudioStreamID *streams = (AudioStreamID *)malloc(size);
+    status = AudioObjectGetPropertyData(deviceID, &streamsAddress, 0, NULL, &size, streams);
+    if (status != noErr) {
+        free(streams);
+        return [NSNull null];
+    }
+    AudioStreamID stream = streams[0];
+    free(streams);
+    
+    AudioObjectPropertyAddress formatAddress = {
+        kAudioStreamPropertyVirtualFormat,
+        kAudioObjectPropertyScopeGlobal,
+        kAudioObjectPropertyElementMain
+    };
+    AudioStreamBasicDescription format;
+    size = sizeof(format);
+    status = AudioObjectGetPropertyData(stream, &formatAddress, 0, NULL, &size, &format);
+    if (status != noErr || format.mFormatID != kAudioFormatLinearPCM) {
+        return [NSNull null];
+    }
+    
+    BOOL isFloat = (format.mFormatFlags & kAudioFormatFlagIsFloat) != 0;
+    return @{
+        @"sampleFormat": [NSString stringWithFormat:@"%@%u", isFloat ? @"float" : @"int", (unsigned int)format.mBitsPerChannel],
+        @"bitsPerChannel": @(format.mBitsPerChannel),
+        @"interleaved": @((format.mFormatFlags & kAudioFormatFlagIsNonInterleaved) == 0)
+    };
+}
+
+// Returns the device's transport type ("builtin", "usb", "aggregate", ...), or
+// "unknown" when it can't be read
+static NSString *getDeviceTransportType(AudioDeviceID deviceID) {
+    AudioObjectPropertyAddress transportAddress = {
+        kAudioDevicePropertyTransportType,
+        kAudioObjectPropertyScopeGlobal,
+        kAudioObjectPropertyElementMain
+    };
+    UInt32 transportType = 0;
+    UInt32 size = sizeof(transportType);
+    OSStatus status = AudioObjectGetPropertyData(deviceID, &transportAddress, 0, NULL, &size, &transportType);
+    if (status != noErr) {
+        return @"unknown";
+    }
+    switch (transportType) {
+        case kAudioDeviceTransportTypeBuiltIn:     return @"builtin";
+        case kAudioDeviceTransportTypeAggregate:   return @"aggregate";
+        case kAudioDeviceTransportTypeVirtual:     return @"virtual";
+        case kAudioDeviceTransportTypeUSB:         return @"usb";
+        case kAudioDeviceTransportTypeFireWire:    return @"firewire";
+        case kAudioDeviceTransportTypeBluetooth:   return @"bluetooth";
+        case kAudioDeviceTransportTypeBluetoothLE: return @"bluetooth";
+        case kAudioDeviceTransportTypeHDMI:        return @"hdmi";
+        case kAudioDeviceTransportTypeDisplayPort: return @"displayport";
+        case kAudioDeviceTransportTypeAirPlay:     return @"airplay";
+        case kAudioDeviceTransportTypeAVB:         return @"avb";
+        case kAudioDeviceTransportTypeThunderbolt: return @"thunderbolt";
+        case kAudioDeviceTransportTypePCI:         return @"pci";
+        default:                                   return @"unknown";
+    }
+}
+
 // Simple test implementation with logging
 char* getAudioInputDevices(void) {
     @autoreleasepool {
@@ -96,6 +187,7 @@ char* getAudioInputDevices(void) {
                 
                 // Get supported sample rates
                 NSMutableArray *sampleRates = [[NSMutableArray alloc] init];
+                NSMutableArray *rateRanges = [[NSMutableArray alloc] init];
                 propertyAddress.mSelector = kAudioDevicePropertyAvailableNominalSampleRates;
                 propertyAddress.mScope = kAudioObjectPropertyScopeGlobal;
                 
@@ -111,10 +203,21 @@ char* getAudioInputDevices(void) {
                             double maxRate = sampleRateRanges[r].mMaximum;
                             NSLog(@"📊 Device %u sample rate range: %.0f - %.0f Hz", (unsigned int)deviceID, minRate, maxRate);
                             
+                            [rateRanges addObject:@[@((int)minRate), @((int)maxRate)]];
+                            
+                            // A discrete rate is reported as a range with min == max
+                            if (minRate == maxRate) {
+                                if (![sampleRates containsObject:@((int)minRate)]) {
+                                    [sampleRates addObject:@((int)minRate)];
+                                }
+                                continue;
+                            }
+                            
                             // Add common sample rates within this range
-                            double commonRates[] = {44100, 48000, 88200, 96000, 176400, 192000};
-                            for (int cr = 0; cr < 6; cr++) {
-                                if (commonRates[cr] >= minRate && commonRates[cr] <= maxRate) {
+                            int commonRates[] = {8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000, 176400, 192000, 352800, 384000};
+                            for (int cr = 0; cr < (int)(sizeof(commonRates) / sizeof(commonRates[0])); cr++) {
+                                if (commonRates[cr] >= minRate && commonRates[cr] <= maxRate &&
+                                    ![sampleRates containsObject:@(commonRates[cr])]) {
                                     [sampleRates addObject:@(commonRates[cr])];
                                 }
                             }
@@ -158,7 +261,8 @@ char* getAudioInputDevices(void) {
                 [inputDevices addObject:@{
                     @"deviceId": @(deviceID), 
                     @"channels": @(inputChannels),
-                    @"sampleRates": sampleRates,
+                    @"sampleRates": [sampleRates sortedArrayUsingSelector:@selector(compare:)],
+                    @"rateRanges": rateRanges,
                     @"bitDepths": bitDepths
                 }];
             } else {
@@ -174,6 +278,7 @@ char* getAudioInputDevices(void) {
             AudioDeviceID deviceID = [inputDevice[@"deviceId"] unsignedIntValue];
             UInt32 channels = [inputDevice[@"channels"] unsignedIntValue];
             NSArray *sampleRates = inputDevice[@"sampleRates"];
+            NSArray *rateRanges = inputDevice[@"rateRanges"];
             NSArray *bitDepths = inputDevice[@"bitDepths"];
             
             NSLog(@"🔍 Getting name for input device ID: %u", (unsigned int)deviceID);
@@ -226,6 +331,11 @@ char* getAudioInputDevices(void) {
             
             NSLog(@"🔍 Input device %u final online status: %s", (unsigned int)deviceID, online ? "YES" : "NO");
             
+            pid_t hogModePID = getDeviceHogModePID(deviceID);
+            if (hogModePID != -1) {
+                NSLog(@"⚠️  Input device %u is hogged by PID %d", (unsigned int)deviceID, (int)hogModePID);
+            }
+            
             // Add device to JSON array
             NSDictionary *deviceJson = @{
                 @"name": realDeviceName,
@@ -233,9 +343,13 @@ char* getAudioInputDevices(void) {
                 @"deviceId": @(deviceID),
                 @"channelCount": @(channels),
                 @"supportedSampleRates": sampleRates,
+                @"sampleRateRanges": rateRanges,
                 @"supportedBitDepths": bitDepths,
                 @"isDefault": @NO,
-                @"isOnline": @(online)
+                @"isOnline": @(online),
+                @"hogModePid": @(hogModePID),
+                @"streamFormat": getDeviceStreamFormat(deviceID, kAudioObjectPropertyScopeInput),
+                @"transportType": getDeviceTransportType(deviceID)
             };
             [jsonDevices addObject:deviceJson];
         }
@@ -341,6 +455,7 @@ char* getAudioOutputDevices(void) {
                 
                 // Get supported sample rates
                 NSMutableArray *sampleRates = [[NSMutableArray alloc] init];
+                NSMutableArray *rateRanges = [[NSMutableArray alloc] init];
                 propertyAddress.mSelector = kAudioDevicePropertyAvailableNominalSampleRates;
                 propertyAddress.mScope = kAudioObjectPropertyScopeGlobal;
                 
@@ -356,10 +471,21 @@ char* getAudioOutputDevices(void) {
                             double maxRate = sampleRateRanges[r].mMaximum;
                             NSLog(@"📊 OUTPUT Device %u sample rate range: %.0f - %.0f Hz", (unsigned int)deviceID, minRate, maxRate);
                             
+                            [rateRanges addObject:@[@((int)minRate), @((int)maxRate)]];
+                            
+                            // A discrete rate is reported as a range with min == max
+                            if (minRate == maxRate) {
+                                if (![sampleRates containsObject:@((int)minRate)]) {
+                                    [sampleRates addObject:@((int)minRate)];
+                                }
+                                continue;
+                            }
+                            
                             // Add common sample rates within this range
-                            double commonRates[] = {44100, 48000, 88200, 96000, 176400, 192000};
-                            for (int cr = 0; cr < 6; cr++) {
-                                if (commonRates[cr] >= minRate && commonRates[cr] <= maxRate) {
+                            int commonRates[] = {8000, 11025, 16000, 22050, 32000, 44100, 48000, 88200, 96000, 176400, 192000, 352800, 384000};
+                            for (int cr = 0; cr < (int)(sizeof(commonRates) / sizeof(commonRates[0])); cr++) {
+                                if (commonRates[cr] >= minRate && commonRates[cr] <= maxRate &&
+                                    ![sampleRates containsObject:@(commonRates[cr])]) {
                                     [sampleRates addObject:@(commonRates[cr])];
                                 }
                             }
@@ -403,7 +529,8 @@ char* getAudioOutputDevices(void) {
                 [outputDevices addObject:@{
                     @"deviceId": @(deviceID), 
                     @"channels": @(outputChannels),
-                    @"sampleRates": sampleRates,
+                    @"sampleRates": [sampleRates sortedArrayUsingSelector:@selector(compare:)],
+                    @"rateRanges": rateRanges,
                     @"bitDepths": bitDepths
                 }];
             } else {
@@ -419,6 +546,7 @@ char* getAudioOutputDevices(void) {
             AudioDeviceID deviceID = [outputDevice[@"deviceId"] unsignedIntValue];
             UInt32 channels = [outputDevice[@"channels"] unsignedIntValue];
             NSArray *sampleRates = outputDevice[@"sampleRates"];
+            NSArray *rateRanges = outputDevice[@"rateRanges"];
             NSArray *bitDepths = outputDevice[@"bitDepths"];
             
             NSLog(@"🔍 Getting name for output device ID: %u", (unsigned int)deviceID);
@@ -471,6 +599,11 @@ char* getAudioOutputDevices(void) {
             
             NSLog(@"🔍 Output device %u final online status: %s", (unsigned int)deviceID, online ? "YES" : "NO");
             
+            pid_t hogModePID = getDeviceHogModePID(deviceID);
+            if (hogModePID != -1) {
+                NSLog(@"⚠️  Output device %u is hogged by PID %d", (unsigned int)deviceID, (int)hogModePID);
+            }
+            
             // Add device to JSON array
             NSDictionary *deviceJson = @{
                 @"name": realDeviceName,
@@ -478,9 +611,13 @@ char* getAudioOutputDevices(void) {
                 @"deviceId": @(deviceID),
                 @"channelCount": @(channels),
                 @"supportedSampleRates": sampleRates,
+                @"sampleRateRanges": rateRanges,
                 @"supportedBitDepths": bitDepths,
                 @"isDefault": @NO,
-                @"isOnline": @(online)
+                @"isOnline": @(online),
+                @"hogModePid": @(hogModePID),
+                @"streamFormat": getDeviceStreamFormat(deviceID, kAudioObjectPropertyScopeOutput),
+                @"transportType": getDeviceTransportType(deviceID)
             };
             [jsonDevices addObject:deviceJson];
         }
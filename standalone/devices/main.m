@@ -12,58 +12,52 @@ int main(int argc, const char * argv[]) {
         char *midiInputStr = getMIDIInputDevices();
         char *midiOutputStr = getMIDIOutputDevices();
         
-        // Parse JSON strings into objects
-        NSError *error;
-        NSData *data;
-        
-        // Audio Input Devices
-        if (audioInputStr) {
-            data = [NSData dataWithBytes:audioInputStr length:strlen(audioInputStr)];
-            NSArray *audioInputDevices = [NSJSONSerialization JSONObjectWithData:data options:0 error:&error];
-            if (!error && audioInputDevices) {
-                systemDevices[@"audioInput"] = audioInputDevices;
+        // Parse JSON strings into objects. A category that fails is recorded
+        // in "errors" without hiding the categories that succeeded.
+        NSMutableDictionary *categoryErrors = [NSMutableDictionary dictionary];
+        id (^parseCategory)(NSString *, char *) = ^id(NSString *category, char *jsonStr) {
+            if (!jsonStr) {
+                categoryErrors[category] = @"enumeration returned no data";
+                return nil;
+            }
+            NSError *parseError = nil;
+            NSData *data = [NSData dataWithBytes:jsonStr length:strlen(jsonStr)];
+            id result = [NSJSONSerialization JSONObjectWithData:data options:0 error:&parseError];
+            free(jsonStr);
+            if (parseError || !result) {
+                categoryErrors[category] = parseError ? parseError.localizedDescription : @"invalid JSON";
+                return nil;
             }
-            free(audioInputStr);
+            return result;
+        };
+        
+        id audioInputDevices = parseCategory(@"audioInput", audioInputStr);
+        if (audioInputDevices) {
+            systemDevices[@"audioInput"] = audioInputDevices;
         }
         
-        // Audio Output Devices  
-        if (audioOutputStr) {
-            data = [NSData dataWithBytes:audioOutputStr length:strlen(audioOutputStr)];
-            NSArray *audioOutputDevices = [NSJSONSerialization JSONObjectWithData:data options:0 error:&error];
-            if (!error && audioOutputDevices) {
-                systemDevices[@"audioOutput"] = audioOutputDevices;
-            }
-            free(audioOutputStr);
+        id audioOutputDevices = parseCategory(@"audioOutput", audioOutputStr);
+        if (audioOutputDevices) {
+            systemDevices[@"audioOutput"] = audioOutputDevices;
         }
         
-        // Default Devices
-        if (defaultDevicesStr) {
-            data = [NSData dataWithBytes:defaultDevicesStr length:strlen(defaultDevicesStr)];
-            NSDictionary *defaultDevices = [NSJSONSerialization JSONObjectWithData:data options:0 error:&error];
-            if (!error && defaultDevices) {
-                systemDevices[@"defaults"] = defaultDevices;
-            }
-            free(defaultDevicesStr);
+        id defaultDevices = parseCategory(@"defaults", defaultDevicesStr);
+        if (defaultDevices) {
+            systemDevices[@"defaults"] = defaultDevices;
         }
         
-        // MIDI Input Devices
-        if (midiInputStr) {
-            data = [NSData dataWithBytes:midiInputStr length:strlen(midiInputStr)];
-            NSArray *midiInputDevices = [NSJSONSerialization JSONObjectWithData:data options:0 error:&error];
-            if (!error && midiInputDevices) {
-                systemDevices[@"midiInput"] = midiInputDevices;
-            }
-            free(midiInputStr);
+        id midiInputDevices = parseCategory(@"midiInput", midiInputStr);
+        if (midiInputDevices) {
+            systemDevices[@"midiInput"] = midiInputDevices;
         }
         
-        // MIDI Output Devices
-        if (midiOutputStr) {
-            data = [NSData dataWithBytes:midiOutputStr length:strlen(midiOutputStr)];
-            NSArray *midiOutputDevices = [NSJSONSerialization JSONObjectWithData:data options:0 error:&error];
-            if (!error && midiOutputDevices) {
-                systemDevices[@"midiOutput"] = midiOutputDevices;
-            }
-            free(midiOutputStr);
+        id midiOutputDevices = parseCategory(@"midiOutput", midiOutputStr);
+        if (midiOutputDevices) {
+            systemDevices[@"midiOutput"] = midiOutputDevices;
+        }
+        
+        if ([categoryErrors count] > 0) {
+            systemDevices[@"errors"] = categoryErrors;
         }
         
         // Add metadata
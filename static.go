@@ -0,0 +1,39 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/shaban/rackless/audio"
+)
+
+// embeddedStatic holds the frontend assets so the binary is self-contained
+//
+//go:embed frontend/static
+var embeddedStatic embed.FS
+
+// preferDiskAssets makes the server serve frontend files from disk (set by --dev)
+var preferDiskAssets bool
+
+// staticFileSystem returns the frontend assets to serve. In dev mode the on-disk
+// files are preferred so edits show up without rebuilding; otherwise, or when the
+// on-disk directory is missing, the embedded copy is used.
+func staticFileSystem() http.FileSystem {
+	if preferDiskAssets {
+		dir := audio.ResolvePath(audio.StaticFilesPath)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return http.Dir(dir)
+		}
+		slog.Warn("static directory not found, serving embedded assets", "dir", dir)
+	}
+
+	assets, err := fs.Sub(embeddedStatic, audio.StaticFilesPath)
+	if err != nil {
+		// The embed pattern guarantees the directory exists
+		panic(err)
+	}
+	return http.FS(assets)
+}
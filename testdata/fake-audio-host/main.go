@@ -0,0 +1,161 @@
+// Command fake-audio-host stands in for standalone/audio-host in integration
+// tests. It speaks the same newline command protocol over stdin/stdout without
+// touching any audio hardware.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+	sampleRate := flag.Float64("sample-rate", 0, "Sample rate")
+	bufferSize := flag.Int("buffer-size", 256, "Buffer size")
+	flag.Int("audio-input-device", 0, "Audio input device ID")
+	flag.Int("audio-input-channel", 0, "Audio input channel")
+	noTone := flag.Bool("no-tone", false, "Disable test tone")
+	flag.Bool("command-mode", false, "Run in command mode")
+	initialLoad := flag.Float64("fake-dsp-load", 0.1, "DSP load to report")
+	flag.Parse()
+
+	running := true
+	testTone := !*noTone
+	toneFreq := 440.0
+	loadedPlugin := ""
+	dspLoad := *initialLoad
+	xruns := 0
+
+	// Test hook: simulate a slow device open
+	if delay, err := time.ParseDuration(os.Getenv("FAKE_AUDIO_HOST_READY_DELAY")); err == nil {
+		time.Sleep(delay)
+	}
+
+	// READY goes to stderr so stdout stays clean for responses.
+	// Test hook: FAKE_AUDIO_HOST_READY_LINE replaces the whole line.
+	readyLine := os.Getenv("FAKE_AUDIO_HOST_READY_LINE")
+	if readyLine == "" {
+		readyLine = fmt.Sprintf(`READY {"sampleRate":%.0f,"bufferSize":%d}`, *sampleRate, *bufferSize)
+	}
+	fmt.Fprintln(os.Stderr, readyLine)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		parts := strings.Fields(scanner.Text())
+		if len(parts) == 0 {
+			continue
+		}
+
+		switch parts[0] {
+		case "ping":
+			fmt.Println("OK: pong")
+		case "start":
+			running = true
+			fmt.Println("OK: started")
+		case "stop":
+			running = false
+			fmt.Println("OK: stopped")
+		case "status":
+			fmt.Printf("STATUS: running=%t sampleRate=%.0f bufferSize=%d testTone=%t toneFreq=%.1f dspLoad=%.2f xruns=%d\n",
+				running, *sampleRate, *bufferSize, testTone, toneFreq, dspLoad, xruns)
+		case "commands":
+			// Test hook: FAKE_AUDIO_HOST_COMMANDS replaces the list, "none" mimics an
+			// old host and "late" an old host that answers only after 400ms
+			commands := os.Getenv("FAKE_AUDIO_HOST_COMMANDS")
+			if commands == "late" {
+				time.Sleep(400 * time.Millisecond)
+				commands = "none"
+			}
+			if commands == "none" {
+				fmt.Printf("ERROR: unknown command '%s' (try 'help')\n", parts[0])
+				continue
+			}
+			if commands == "" {
+				commands = "ping start stop status get-input-level tone load-plugin unload-plugin list-plugins quit exit commands"
+			}
+			fmt.Printf("COMMANDS: %s\n", commands)
+		case "getenv":
+			// Test hook: report the environment audio-host was launched with
+			if len(parts) < 2 {
+				fmt.Println("ERROR: name required")
+				continue
+			}
+			fmt.Printf("OK: %s\n", os.Getenv(parts[1]))
+		case "pwd":
+			// Test hook: report the working directory audio-host was launched in
+			dir, err := os.Getwd()
+			if err != nil {
+				fmt.Printf("ERROR: %v\n", err)
+				continue
+			}
+			fmt.Printf("OK: %s\n", dir)
+		case "get-input-level":
+			fmt.Println("LEVEL: peak=-12.0 rms=-18.5")
+		case "set-dsp-load":
+			// Test hook: change the DSP load reported by status
+			if len(parts) < 2 {
+				fmt.Println("ERROR: load required")
+				continue
+			}
+			value, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil {
+				fmt.Println("ERROR: invalid load")
+				continue
+			}
+			dspLoad = value
+			fmt.Println("OK: dsp load set")
+		case "add-xruns":
+			// Test hook: simulate dropouts
+			count := 1
+			if len(parts) >= 2 {
+				count, _ = strconv.Atoi(parts[1])
+			}
+			xruns += count
+			fmt.Println("OK: xruns added")
+		case "tone":
+			switch {
+			case len(parts) >= 2 && parts[1] == "on":
+				testTone = true
+				fmt.Println("OK: tone enabled")
+			case len(parts) >= 2 && parts[1] == "off":
+				testTone = false
+				fmt.Println("OK: tone disabled")
+			case len(parts) >= 3 && parts[1] == "freq":
+				freq, err := strconv.ParseFloat(parts[2], 64)
+				if err != nil || freq <= 0 || freq > 20000 {
+					fmt.Println("ERROR: invalid frequency (0-20000 Hz)")
+					continue
+				}
+				toneFreq = freq
+				fmt.Printf("OK: frequency set to %.1f\n", freq)
+			default:
+				fmt.Println("ERROR: unknown tone command")
+			}
+		case "load-plugin":
+			if len(parts) < 2 {
+				fmt.Println("ERROR: failed to load plugin")
+				continue
+			}
+			loadedPlugin = parts[1]
+			fmt.Println("OK: plugin loaded")
+		case "unload-plugin":
+			loadedPlugin = ""
+			fmt.Println("OK: plugin unloaded")
+		case "list-plugins":
+			if loadedPlugin == "" {
+				fmt.Println("LOADED: none")
+			} else {
+				fmt.Printf("LOADED: %s\n", loadedPlugin)
+			}
+		case "quit", "exit":
+			fmt.Println("OK: goodbye")
+			return
+		default:
+			fmt.Printf("ERROR: unknown command '%s' (try 'help')\n", parts[0])
+		}
+	}
+}